@@ -0,0 +1,30 @@
+package backend
+
+import (
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/itype/imguiconv"
+	"github.com/Edgaru089/implot-go-example/render"
+)
+
+// AtlasImage shows the named image of an atlas with imgui.Image.
+// If size is zero, the image is shown in its size in pixels.
+//
+// It returns false (and shows nothing) if the atlas has no such image.
+func AtlasImage(atlas *render.Atlas, name string, size imgui.Vec2) bool {
+	e, ok := atlas.Entry(name)
+	if !ok {
+		return false
+	}
+	if size == (imgui.Vec2{}) {
+		size = imgui.Vec2{X: float32(e.Rect.Width), Y: float32(e.Rect.Height)}
+	}
+	imgui.ImageV(
+		imgui.TextureID(atlas.Texture().Handle()),
+		size,
+		imguiconv.Vec2(e.UV0()),
+		imguiconv.Vec2(e.UV1()),
+		imgui.Vec4{X: 1, Y: 1, Z: 1, W: 1},
+		imgui.Vec4{},
+	)
+	return true
+}
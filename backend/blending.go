@@ -0,0 +1,32 @@
+package backend
+
+import (
+	"github.com/Edgaru089/imgui-go/v4"
+)
+
+var (
+	srgb          bool
+	premultiplied = make(map[imgui.TextureID]bool)
+)
+
+// SetSRGB sets if Render draws with sRGB-correct blending.
+//
+// When enabled, GL_FRAMEBUFFER_SRGB is turned on while drawing and the imgui colors
+// (which are sRGB) are converted to linear, so blending happens in linear space.
+// Textures should then be in an sRGB format, like render.FormatSRGBA8, to be decoded too.
+func SetSRGB(enabled bool) {
+	srgb = enabled
+}
+
+// SetTexturePremultiplied marks a texture drawn through imgui (with imgui.Image and such)
+// as having its colors premultiplied by alpha, or not, which is the default.
+//
+// Textures are RGBA; single-channel textures should be swizzled with render.SwizzleAlpha
+// (for masks, like the font atlas) or render.SwizzleGray (for grayscale images).
+func SetTexturePremultiplied(id imgui.TextureID, enabled bool) {
+	if enabled {
+		premultiplied[id] = true
+	} else {
+		delete(premultiplied, id)
+	}
+}
@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DrawCallbackInfo is passed to a DrawCallback when it is run by Render.
+type DrawCallbackInfo struct {
+	ClipRect        imgui.Vec4  // Clip rectangle (x1, y1, x2, y2) in framebuffer pixels, from the top-left
+	Projection      mgl32.Mat4  // Projection from imgui display coordinates to clip space
+	DisplaySize     imgui.Vec2  // Size of the imgui display
+	FramebufferSize itype.Vec2i // Size of the framebuffer drawn into
+}
+
+// DrawCallback is a function running custom OpenGL code in the middle of imgui rendering.
+//
+// It is called with the scissor test set to the clip rectangle, and may change any GL state:
+// the backend sets up its own state again afterwards.
+type DrawCallback func(info DrawCallbackInfo)
+
+// Draw callbacks are queued as zero-sized images with a texture ID that is never
+// a real texture, counting down from the largest ID; Render spots these IDs in
+// the draw commands and runs the callback instead of drawing.
+var drawCallbacks []DrawCallback
+
+const drawCallbackMaxID = ^imgui.TextureID(0)
+
+// AddDrawCallback queues a callback in a draw list, usually imgui.WindowDrawList(),
+// to be run by Render in order with the other draw commands of the list.
+//
+// Callbacks are dropped after each Render, so they must be added again every frame.
+func AddDrawCallback(list imgui.DrawList, callback DrawCallback) {
+	id := drawCallbackMaxID - imgui.TextureID(len(drawCallbacks))
+	drawCallbacks = append(drawCallbacks, callback)
+	list.AddImage(id, imgui.Vec2{}, imgui.Vec2{})
+}
+
+// drawCallbackOf returns the callback queued with the texture ID, if any.
+func drawCallbackOf(id imgui.TextureID) (callback DrawCallback, ok bool) {
+	index := int(drawCallbackMaxID - id)
+	if index < 0 || index >= len(drawCallbacks) {
+		return nil, false
+	}
+	return drawCallbacks[index], true
+}
+
+// resetDrawCallbacks drops the callbacks of the frame.
+func resetDrawCallbacks() {
+	drawCallbacks = drawCallbacks[:0]
+}
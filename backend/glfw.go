@@ -32,6 +32,12 @@ func Init(window *glfw.Window) {
 	renderInit()
 }
 
+// Shutdown deletes the GL objects created by the backend.
+// The GL context must still be current.
+func Shutdown() {
+	renderShutdown()
+}
+
 // NewFrame marks the begin of a render pass.
 func NewFrame() {
 	dsx, dsy := win.GetSize()
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"image"
+
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/Edgaru089/implot-go-example/render"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// offscreenFrames is the number of frames rendered by RenderOffscreen,
+// so that auto-sized windows and plots can settle before the last one is kept.
+const offscreenFrames = 3
+
+// RenderOffscreen renders the UI built by draw into an offscreen image of size pixels,
+// and returns it. The on-screen UI and its imgui/ImPlot state are left untouched.
+//
+// draw is called inside a window covering the whole image, in a temporary imgui context
+// sharing the font atlas of the current one, and should not call imgui.NewFrame or Render.
+// scale is the size of a UI pixel in image pixels, like the content scale of a HiDPI display:
+// an image of 4000 pixels at scale 2 lays out like a 2000-pixel window. Text is scaled from
+// the font atlas, so it is sharpest at scale 1.
+//
+// It must be called on the main thread, outside of an imgui frame of the current context
+// (for example, right after Render).
+func RenderOffscreen(size itype.Vec2i, scale float32, draw func()) (*image.RGBA, error) {
+	fb, err := render.NewFramebuffer(size, render.FramebufferOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer fb.Free()
+
+	last, err := imgui.CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	fonts := imgui.CurrentIO().Fonts()
+	ctx := imgui.CreateContext(&fonts)
+	defer func() {
+		last.SetCurrent()
+		ctx.Destroy()
+	}()
+	ctx.SetCurrent()
+
+	display := imgui.Vec2{X: float32(size[0]) / scale, Y: float32(size[1]) / scale}
+	offio := imgui.CurrentIO()
+	offio.SetIniFilename("")
+	offio.SetDisplaySize(display)
+	// The same renderer flags as the on-screen context (see renderInit): without
+	// RendererHasVtxOffset, a window over 64k vertices, like a dense plot exported
+	// at a high resolution, overflows the 16-bit indices and panics
+	offio.SetBackendFlags(imgui.BackendFlagsRendererHasVtxOffset)
+	offio.SetDeltaTime(1.0 / 60)
+
+	var clear [4]float32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &clear[0])
+	defer gl.ClearColor(clear[0], clear[1], clear[2], clear[3])
+
+	fb.Bind()
+	defer fb.Unbind()
+	for i := 0; i < offscreenFrames; i++ {
+		imgui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(display)
+		imgui.BeginV("##offscreen", nil, imgui.WindowFlagsNoDecoration|imgui.WindowFlagsNoSavedSettings)
+		draw()
+		imgui.End()
+		imgui.Render()
+
+		gl.ClearColor(0, 0, 0, 0)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+		renderDrawData(imgui.RenderedDrawData(), display, size[0], size[1])
+	}
+
+	return fb.Image(), nil
+}
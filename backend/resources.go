@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/render"
+)
+
+// formatBytes formats a memory size in bytes.
+func formatBytes(bytes int) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
+
+// ShowResourcesWindow shows a window listing the live GPU resources of the render package,
+// with their estimated memory. Creation stacks are shown in builds with the debug tag.
+func ShowResourcesWindow(open *bool) {
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 400, Y: 300}, imgui.ConditionFirstUseEver)
+	if !imgui.BeginV("GPU Resources", open, 0) {
+		imgui.End()
+		return
+	}
+
+	res := render.LiveResources()
+	var total int
+	for _, r := range res {
+		total += r.Memory
+	}
+	imgui.Text(fmt.Sprintf("%d resources, %s", len(res), formatBytes(total)))
+
+	if imgui.BeginTableV("##Resources", 3, imgui.TableFlagsBorders|imgui.TableFlagsRowBg|imgui.TableFlagsScrollY, imgui.Vec2{}, 0) {
+		imgui.TableSetupScrollFreeze(0, 1)
+		imgui.TableSetupColumn("Kind")
+		imgui.TableSetupColumn("Handle")
+		imgui.TableSetupColumn("Memory")
+		imgui.TableHeadersRow()
+
+		for _, r := range res {
+			imgui.TableNextRow()
+			imgui.TableNextColumn()
+			imgui.Text(r.Kind.String())
+			if r.Stack != "" && imgui.IsItemHovered() {
+				imgui.SetTooltip(r.Stack)
+			}
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprint(r.Handle))
+			imgui.TableNextColumn()
+			imgui.Text(formatBytes(r.Memory))
+		}
+		imgui.EndTable()
+	}
+	imgui.End()
+}
@@ -2,13 +2,27 @@
 
 
 uniform sampler2D tex;
+uniform bool premultiplied; // tex has premultiplied alpha
+uniform bool srgb;          // drawing to an sRGB framebuffer: convert the vertex colors to linear
 
 in vec2 fragUV;
 in vec4 fragColor;
 
 out vec4 outputColor;
 
+// Alpha-only textures (like the font atlas) are swizzled to white with alpha,
+// so every texture is sampled as RGBA. The output has premultiplied alpha.
 void main() {
-	outputColor = vec4(fragColor.rgb, fragColor.a * texture(tex, fragUV.st).r);
-}
+	vec4 color = fragColor;
+	if (srgb) {
+		color.rgb = pow(color.rgb, vec3(2.2));
+	}
 
+	vec4 texel = texture(tex, fragUV.st);
+	if (premultiplied) {
+		outputColor = texel * vec4(color.rgb * color.a, color.a);
+	} else {
+		vec4 c = color * texel;
+		outputColor = vec4(c.rgb * c.a, c.a);
+	}
+}
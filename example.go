@@ -2,11 +2,17 @@ package main
 
 import (
 	"fmt"
+	"image/png"
 	"math"
 	"math/rand"
+	"os"
 	"runtime"
 
 	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/backend"
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/Edgaru089/implot-go-example/itype/imguiconv"
+	"github.com/Edgaru089/implot-go-example/render"
 )
 
 var plotSize = imgui.Vec2{X: -1, Y: 200}
@@ -104,27 +110,156 @@ func showShadedLines() {
 	}
 }
 
-func showScatter() {
+// scatterSeries is a scatter plot series with a spatial index for hovering.
+type scatterSeries struct {
+	name   string
+	points []itype.Vec2d
+	tree   *itype.KDTree // nil until built by buildScatterTrees
+}
+
+var (
+	scatterData        []*scatterSeries
+	scatterLogScale    = false
+	scatterShowBig     = false
+	scatterResetLimits = false // Reset the axis limits on the next frame, after a scale change
+	scatterTreeGen     int     // Incremented by buildScatterTrees, to drop trees built for an old scale
+)
+
+// scatterHoverRadius is how far from a point, in pixels, the mouse can be to hover it.
+const scatterHoverRadius = 8
+
+// scatterCloudSize is the number of points in the large "Cloud" series.
+const scatterCloudSize = 1000000
+
+func initScatter() {
 	rand.Seed(0)
-	var s1 [100]imgui.Point
-	var s2 [50]imgui.Point
-	for i := 0; i < 100; i++ {
-		s1[i].X = float64(i) * 0.01
-		s1[i].Y = s1[i].X + 0.1*rand.Float64()
+	s1 := make([]itype.Vec2d, 100)
+	s2 := make([]itype.Vec2d, 50)
+	for i := range s1 {
+		s1[i][0] = float64(i) * 0.01
+		s1[i][1] = s1[i][0] + 0.1*rand.Float64()
+	}
+	for i := range s2 {
+		s2[i][0] = 0.25 + 0.2*rand.Float64()
+		s2[i][1] = 0.75 + 0.2*rand.Float64()
+	}
+	cloud := make([]itype.Vec2d, scatterCloudSize)
+	for i := range cloud {
+		cloud[i][0] = math.Abs(0.5 + 0.15*rand.NormFloat64())
+		cloud[i][1] = math.Abs(0.5 + 0.15*rand.NormFloat64())
+	}
+	scatterData = []*scatterSeries{
+		{name: "Data 1", points: s1},
+		{name: "Data 2", points: s2},
+		{name: "Cloud", points: cloud},
+	}
+	buildScatterTrees()
+}
+
+// buildScatterTrees indexes the series for the current scale in the background,
+// as the Cloud takes a good part of a second. The trees are handed over on the
+// render thread with render.Do; until then the series cannot be hovered.
+func buildScatterTrees() {
+	scatterTreeGen++
+	gen := scatterTreeGen
+	_, scale := scatterScales()
+	for _, s := range scatterData {
+		s := s
+		s.tree = nil
+		go func() {
+			tree := itype.NewKDTree(s.points, scale, scale)
+			render.Do(func() {
+				if gen == scatterTreeGen {
+					s.tree = tree
+				}
+			})
+		}()
+	}
+}
+
+// scatterScales returns the axis flags and index scale for the log scale setting.
+func scatterScales() (imgui.AxisFlags, itype.AxisScale) {
+	if scatterLogScale {
+		return imgui.AxisFlags_LogScale, itype.ScaleLog10
+	}
+	return 0, itype.ScaleLinear
+}
+
+func showScatter() {
+	if scatterData == nil {
+		initScatter()
+	}
+	series := scatterData
+	if !scatterShowBig {
+		series = series[:2]
 	}
-	for i := 0; i < 50; i++ {
-		s2[i].X = 0.25 + 0.2*rand.Float64()
-		s2[i].Y = 0.75 + 0.2*rand.Float64()
+
+	if imgui.Checkbox("Log Scale", &scatterLogScale) {
+		scatterResetLimits = true
+		buildScatterTrees()
+	}
+	imgui.SameLine()
+	imgui.Checkbox(fmt.Sprintf("Cloud (%d points)", scatterCloudSize), &scatterShowBig)
+	for _, s := range series {
+		if s.tree == nil {
+			imgui.SameLine()
+			imgui.Text("(indexing...)")
+			break
+		}
 	}
 
+	var hovered *scatterSeries
+	hoveredIndex := -1
 	if imgui.BeginPlotV("Scatter", plotSize, 0) {
-		imgui.PlotScatterP("Data 1", s1[:])
+		flags, scale := scatterScales()
+		imgui.SetupAxes("x", "y", flags, flags)
+		cond := imgui.ConditionOnce
+		if scatterResetLimits {
+			cond, scatterResetLimits = imgui.ConditionAlways, false
+		}
+		if scatterLogScale {
+			imgui.SetupAxesLimits(0.01, 1, 0.01, 1.1, imgui.Cond(cond))
+		} else {
+			imgui.SetupAxesLimits(0, 1, 0, 1.1, imgui.Cond(cond))
+		}
+		imgui.SetupFinish()
+
+		// Find the point under the mouse, with the plot area and limits of this frame
+		mapping := imguiconv.CurrentPlotMapping(scale, scale)
+		mouse := imgui.MousePos()
+		mousePx := itype.Vec2d{float64(mouse.X), float64(mouse.Y)}
+		if imgui.IsWindowHovered() && mapping.Area.Contains(mousePx) {
+			p, weight, best := mapping.FromPixels(mousePx), mapping.Weight(), float64(scatterHoverRadius)
+			for _, s := range series {
+				if s.tree == nil {
+					continue
+				}
+				if i, dist := s.tree.Nearest(p, weight, best); i != -1 {
+					hovered, hoveredIndex, best = s, i, dist
+				}
+			}
+		}
+
+		if scatterShowBig {
+			imgui.SetNextMarkerStyle(imgui.Marker_Circle, 1, imgui.AutoColor, imgui.Auto, imgui.AutoColor)
+			imguiconv.PlotScatterP("Cloud", scatterData[2].points)
+		}
+		imguiconv.PlotScatterP("Data 1", scatterData[0].points)
 		imgui.PushPlotStyleVar(imgui.PlotStyleVar_FillAlpha, 0.25)
 		imgui.SetNextMarkerStyle(imgui.Marker_Square, 6, imgui.AutoColor, imgui.Auto, imgui.AutoColor)
-		imgui.PlotScatterP("Data 2", s2[:])
+		imguiconv.PlotScatterP("Data 2", scatterData[1].points)
 		imgui.PopPlotStyleVar()
+		if hovered != nil {
+			imgui.SetNextMarkerStyle(imgui.Marker_Circle, 8, imgui.Vec4{}, 2, imgui.Vec4{X: 1, Y: 1, Z: 1, W: 1})
+			imguiconv.PlotScatterP("##Hovered", hovered.points[hoveredIndex:hoveredIndex+1])
+		}
 		imgui.EndPlot()
 	}
+
+	if hovered != nil {
+		p := hovered.points[hoveredIndex]
+		imgui.SetTooltipf("%s #%d\nx = %.4f\ny = %.4f", hovered.name, hoveredIndex, p[0], p[1])
+	}
 }
 
 func showStairs() {
@@ -272,11 +407,42 @@ func showTickLabels() {
 	}
 }
 
+// exportPlot renders a plot function offscreen at 4000x3000 and saves it as a PNG file.
+// The plot fills the image instead of using plotSize.
+//
+// The offscreen imgui context must be set up like the on-screen one, as RenderOffscreen does:
+// at 4000x3000 a dense plot easily needs more vertices than 16-bit indices can reach without
+// the renderer's vertex offsets.
+func exportPlot(filename string, show func()) {
+	// RenderOffscreen cannot run in the middle of a frame
+	render.Do(func() {
+		saved := plotSize
+		plotSize = imgui.Vec2{X: -1, Y: -1}
+		img, err := backend.RenderOffscreen(itype.Vec2i{4000, 3000}, 4, show)
+		plotSize = saved
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "export:", err)
+			return
+		}
+
+		f, err := os.Create(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "export:", err)
+			return
+		}
+		defer f.Close()
+		if err = png.Encode(f, img); err != nil {
+			fmt.Fprintln(os.Stderr, "export:", err)
+		}
+	})
+}
+
 func example() {
 	imgui.SetNextWindowSizeV(imgui.Vec2{X: 400, Y: 600}, imgui.ConditionAppearing)
 	if imgui.Begin("ImPlot-Go example") {
 
 		imgui.Text(fmt.Sprintf("ImPlot-Go says hello. (%s)\ncompiled by %s/%s [%s/%s]", imgui.PlotVersion(), runtime.Compiler, runtime.Version(), runtime.GOOS, runtime.GOARCH))
+		imgui.Checkbox("Show GPU resources", &showResources)
 
 		if imgui.BeginTabBar("MainTab") {
 			if imgui.BeginTabItem("Plots") {
@@ -285,6 +451,9 @@ func example() {
 					showLine()
 				}
 				if imgui.CollapsingHeader("Shaded Plots") {
+					if imgui.Button("Export as PNG") {
+						exportPlot("shaded.png", showShaded)
+					}
 					showShaded()
 					showShadedLines()
 				}
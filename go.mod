@@ -7,10 +7,10 @@ require (
 	github.com/go-gl/gl v0.0.0-20211210172815-726fda9656d6
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20211213063430-748e38ca8aec
 	github.com/go-gl/mathgl v1.0.0
+	golang.org/x/image v0.0.0-20190321063152-3fc05d484e9f
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inkyblackness/imgui-go/v4 v4.5.0 // indirect
-	golang.org/x/image v0.0.0-20190321063152-3fc05d484e9f // indirect
 )
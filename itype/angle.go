@@ -1,6 +1,11 @@
 package itype
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
 
 // Angle wraps a angle in the form of radian float32.
 //
@@ -9,9 +14,15 @@ type Angle float32
 
 func normalizeRadians(rad float64) float64 {
 	if rad < 0 {
-		return 2*math.Pi - math.Mod(-rad, 2*math.Pi)
+		rad = 2*math.Pi - math.Mod(-rad, 2*math.Pi)
+	} else {
+		rad = math.Mod(rad, 2*math.Pi)
 	}
-	return math.Mod(rad, 2*math.Pi)
+	// Values close below 2Pi round up to it as float32
+	if float32(rad) >= float32(2*math.Pi) {
+		return 0
+	}
+	return rad
 }
 
 // Radians construct an Angle from a radian value.
@@ -33,3 +44,108 @@ func (a Angle) Radians() float32 {
 func (a Angle) Degrees() float32 {
 	return float32(normalizeRadians(float64(a)) * 180 / math.Pi)
 }
+
+// AngleOf returns the angle of the vector from the X+ axis, counter-clockwise.
+func AngleOf(v Vec2d) Angle {
+	return Angle(normalizeRadians(math.Atan2(v[1], v[0])))
+}
+
+// Add returns a+b, wrapped around.
+func (a Angle) Add(b Angle) Angle {
+	return Angle(normalizeRadians(float64(a) + float64(b)))
+}
+
+// Sub returns a-b, wrapped around.
+func (a Angle) Sub(b Angle) Angle {
+	return Angle(normalizeRadians(float64(a) - float64(b)))
+}
+
+// Diff returns the shortest signed difference from a to b in radians, in [-Pi, Pi):
+// positive if b is counter-clockwise from a.
+func (a Angle) Diff(b Angle) float32 {
+	d := normalizeRadians(float64(b) - float64(a))
+	if d >= math.Pi {
+		d -= 2 * math.Pi
+	}
+	return float32(d)
+}
+
+// Lerp interpolates from a (t=0) to b (t=1) along the short arc, by interpolating
+// the direction vectors linearly. It is cheap, but the angle does not change at
+// a constant rate, unlike Slerp.
+//
+// Where the interpolated vector is (close to) zero, like halfway between opposite
+// angles, it has no direction, and Lerp returns Slerp instead.
+func (a Angle) Lerp(b Angle, t float32) Angle {
+	v := a.Vector().Lerp(b.Vector(), float64(t))
+	if v.Length() < 1e-6 {
+		return a.Slerp(b, t)
+	}
+	return AngleOf(v)
+}
+
+// Slerp interpolates from a (t=0) to b (t=1) along the short arc, at a constant rate.
+// For opposite angles it goes clockwise, as Diff returns -Pi.
+func (a Angle) Slerp(b Angle, t float32) Angle {
+	return Angle(normalizeRadians(float64(a) + float64(a.Diff(b))*float64(t)))
+}
+
+// Sin returns the sine of the angle.
+func (a Angle) Sin() float64 {
+	return math.Sin(float64(a))
+}
+
+// Cos returns the cosine of the angle.
+func (a Angle) Cos() float64 {
+	return math.Cos(float64(a))
+}
+
+// Vector returns the unit vector at the angle from the X+ axis, counter-clockwise.
+func (a Angle) Vector() Vec2d {
+	s, c := math.Sincos(float64(a))
+	return Vec2d{c, s}
+}
+
+// Polar returns the vector at the angle with the given length.
+func (a Angle) Polar(length float64) Vec2d {
+	return a.Vector().Multiply(length)
+}
+
+// String formats the angle in degrees, like "45°".
+func (a Angle) String() string {
+	return strconv.FormatFloat(float64(a.Degrees()), 'f', -1, 32) + "°"
+}
+
+// Format formats the angle in degrees ("45.0°") or radians ("0.785rad")
+// with prec digits after the decimal point, or as few as needed if prec is -1.
+func (a Angle) Format(degrees bool, prec int) string {
+	if degrees {
+		return strconv.FormatFloat(float64(a.Degrees()), 'f', prec, 32) + "°"
+	}
+	return strconv.FormatFloat(float64(a.Radians()), 'f', prec, 32) + "rad"
+}
+
+// ParseAngle parses an angle in degrees ("45°", "45deg", or just "45")
+// or radians ("0.785rad"). NaN and infinite values are rejected.
+func ParseAngle(s string) (Angle, error) {
+	str := strings.TrimSpace(s)
+	radians := false
+	switch {
+	case strings.HasSuffix(str, "°"):
+		str = strings.TrimSuffix(str, "°")
+	case strings.HasSuffix(str, "deg"):
+		str = strings.TrimSuffix(str, "deg")
+	case strings.HasSuffix(str, "rad"):
+		str = strings.TrimSuffix(str, "rad")
+		radians = true
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("itype: invalid angle %q", s)
+	}
+	if !radians {
+		v = v * math.Pi / 180
+	}
+	return Angle(normalizeRadians(v)), nil
+}
@@ -0,0 +1,103 @@
+package itype
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAngleDiff(t *testing.T) {
+	runOpCases(t, []opCase{
+		{"same", Degrees(30).Diff(Degrees(30)), float32(0)},
+		{"ccw", Degrees(10).Diff(Degrees(50)), float32(40 * math.Pi / 180)},
+		{"cw", Degrees(50).Diff(Degrees(10)), float32(-40 * math.Pi / 180)},
+		{"across 0 ccw", Degrees(350).Diff(Degrees(20)), float32(30 * math.Pi / 180)},
+		{"across 0 cw", Degrees(20).Diff(Degrees(350)), float32(-30 * math.Pi / 180)},
+		{"opposite", Degrees(0).Diff(Degrees(180)), float32(-math.Pi)},
+	})
+}
+
+// angleCase compares two angles, allowing for float32 rounding across 0.
+type angleCase struct {
+	name      string
+	got, want Angle
+}
+
+func runAngleCases(t *testing.T, cases []angleCase) {
+	t.Helper()
+	for _, c := range cases {
+		if d := c.got.Diff(c.want); math.Abs(float64(d)) > 1e-5 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestAngleNormalize(t *testing.T) {
+	runAngleCases(t, []angleCase{
+		{"Degrees(-90)", Degrees(-90), Degrees(270)},
+		{"Degrees(720)", Degrees(720), 0},
+		{"Radians(-2Pi)", Radians(-2 * math.Pi), 0},
+		{"Add", Degrees(300).Add(Degrees(90)), Degrees(30)},
+		{"Sub", Degrees(30).Sub(Degrees(90)), Degrees(300)},
+		{"AngleOf", AngleOf(Vec2d{0, -2}), Degrees(270)},
+	})
+	for _, a := range []Angle{Degrees(-90), Degrees(720), Radians(-2 * math.Pi), Degrees(30).Sub(Degrees(90))} {
+		if a < 0 || float64(a) >= 2*math.Pi {
+			t.Errorf("%v (%v rad) out of [0, 2Pi)", a, float64(a))
+		}
+	}
+	// Just below 2Pi rounds to 2Pi as float32, and must wrap to 0
+	if a := Radians(float32(math.Nextafter(2*math.Pi, 0))); a < 0 || float64(a) >= 2*math.Pi {
+		t.Errorf("Radians(2Pi-) = %v, out of [0, 2Pi)", float64(a))
+	}
+}
+
+func TestAngleInterpolate(t *testing.T) {
+	runAngleCases(t, []angleCase{
+		{"Slerp", Degrees(10).Slerp(Degrees(50), 0.25), Degrees(20)},
+		{"Slerp across 0", Degrees(340).Slerp(Degrees(20), 0.5), 0},
+		{"Slerp opposite", Degrees(0).Slerp(Degrees(180), 0.5), Degrees(270)},
+		{"Lerp ends", Degrees(10).Lerp(Degrees(80), 1), Degrees(80)},
+		{"Lerp half", Degrees(10).Lerp(Degrees(80), 0.5), Degrees(45)},
+		{"Lerp across 0", Degrees(340).Lerp(Degrees(20), 0.5), 0},
+		{"Lerp opposite", Degrees(90).Lerp(Degrees(270), 0.5), Degrees(90).Slerp(Degrees(270), 0.5)},
+	})
+}
+
+func TestParseAngle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Angle
+	}{
+		{"45", Degrees(45)},
+		{" 45° ", Degrees(45)},
+		{"45deg", Degrees(45)},
+		{"-90 deg", Degrees(270)},
+		{"3.14159265rad", Degrees(180)},
+		{"720", 0},
+	}
+	for _, tt := range tests {
+		a, err := ParseAngle(tt.in)
+		if err != nil {
+			t.Errorf("ParseAngle(%q) error: %v", tt.in, err)
+			continue
+		}
+		runAngleCases(t, []angleCase{{"ParseAngle(" + tt.in + ")", a, tt.want}})
+	}
+
+	for _, in := range []string{"", "deg", "abc", "45 degrees", "NaN", "nanrad", "Inf", "-inf°", "+Infinity"} {
+		if a, err := ParseAngle(in); err == nil {
+			t.Errorf("ParseAngle(%q) = %v, want an error", in, a)
+		}
+	}
+
+	// String and Format parse back
+	a := Degrees(123.5)
+	for _, s := range []string{a.String(), a.Format(true, 3), a.Format(false, -1)} {
+		b, err := ParseAngle(s)
+		if err != nil {
+			t.Errorf("ParseAngle(%q) error: %v", s, err)
+			continue
+		}
+		runAngleCases(t, []angleCase{{"ParseAngle(" + s + ")", b, a}})
+	}
+}
@@ -1,5 +1,12 @@
 package itype
 
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
 // Dataset describes arbitrary data stored in String-Interface{} pairs.
 // This is the major way how complicated data is stored in blocks and entities.
 //
@@ -9,3 +16,189 @@ package itype
 //   - bool
 //   - string
 type Dataset map[string]interface{}
+
+// ValidDatasetValue returns true if the value can be stored in a Dataset.
+func ValidDatasetValue(value interface{}) bool {
+	switch value.(type) {
+	case int, float64, bool, string:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate returns an error for the first key (in sorted order) holding a value
+// of a type a Dataset should not hold.
+func (d Dataset) Validate() error {
+	for _, key := range d.Keys() {
+		if !ValidDatasetValue(d[key]) {
+			return fmt.Errorf("itype: dataset key %q holds unsupported type %T", key, d[key])
+		}
+	}
+	return nil
+}
+
+// Set sets a value, returning an error (and leaving the Dataset unchanged)
+// if it is not an int, float64, bool or string.
+func (d Dataset) Set(key string, value interface{}) error {
+	if !ValidDatasetValue(value) {
+		return fmt.Errorf("itype: cannot store %T in dataset key %q", value, key)
+	}
+	d[key] = value
+	return nil
+}
+
+func (d Dataset) SetInt(key string, value int)       { d[key] = value }
+func (d Dataset) SetFloat(key string, value float64) { d[key] = value }
+func (d Dataset) SetBool(key string, value bool)     { d[key] = value }
+func (d Dataset) SetString(key string, value string) { d[key] = value }
+
+// GetInt returns an int value. A float64 holding a whole number that fits in an int
+// (as decoded from JSON) is converted; ok is false for other values or a missing key.
+func (d Dataset) GetInt(key string) (value int, ok bool) {
+	switch v := d[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt && v < -math.MinInt {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// GetFloat returns a float64 value. An int value is converted.
+func (d Dataset) GetFloat(key string) (value float64, ok bool) {
+	switch v := d[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// GetBool returns a bool value.
+func (d Dataset) GetBool(key string) (value bool, ok bool) {
+	value, ok = d[key].(bool)
+	return
+}
+
+// GetString returns a string value.
+func (d Dataset) GetString(key string) (value string, ok bool) {
+	value, ok = d[key].(string)
+	return
+}
+
+// IntOr returns the value like GetInt, or def if there is none.
+func (d Dataset) IntOr(key string, def int) int {
+	if v, ok := d.GetInt(key); ok {
+		return v
+	}
+	return def
+}
+
+// FloatOr returns the value like GetFloat, or def if there is none.
+func (d Dataset) FloatOr(key string, def float64) float64 {
+	if v, ok := d.GetFloat(key); ok {
+		return v
+	}
+	return def
+}
+
+// BoolOr returns the value like GetBool, or def if there is none.
+func (d Dataset) BoolOr(key string, def bool) bool {
+	if v, ok := d.GetBool(key); ok {
+		return v
+	}
+	return def
+}
+
+// StringOr returns the value like GetString, or def if there is none.
+func (d Dataset) StringOr(key string, def string) string {
+	if v, ok := d.GetString(key); ok {
+		return v
+	}
+	return def
+}
+
+// Keys returns the keys in sorted order.
+func (d Dataset) Keys() []string {
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Range calls f for every key and value in sorted key order, until f returns false.
+func (d Dataset) Range(f func(key string, value interface{}) bool) {
+	for _, key := range d.Keys() {
+		if !f(key, d[key]) {
+			return
+		}
+	}
+}
+
+// Copy returns a copy of the Dataset. The values are all plain values,
+// so the copy shares nothing with the original.
+func (d Dataset) Copy() Dataset {
+	if d == nil {
+		return nil
+	}
+	c := make(Dataset, len(d))
+	for key, value := range d {
+		c[key] = value
+	}
+	return c
+}
+
+// Equal returns true if the two Datasets have the same keys with values of the same
+// types and values. An int is never equal to a float64.
+func (d Dataset) Equal(other Dataset) bool {
+	if len(d) != len(other) {
+		return false
+	}
+	for key, value := range d {
+		v, ok := other[key]
+		if !ok || !reflect.DeepEqual(v, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge sets every key of other into the Dataset, overwriting existing values.
+func (d Dataset) Merge(other Dataset) {
+	for key, value := range other {
+		d[key] = value
+	}
+}
+
+// DiffDataset compares two Datasets, returning the keys of to that are new or changed
+// (with their values in to) and the keys of from missing in to, in sorted order.
+//
+// from.ApplyDiff(DiffDataset(from, to)) makes from equal to to.
+func DiffDataset(from, to Dataset) (changed Dataset, removed []string) {
+	changed = make(Dataset)
+	for key, value := range to {
+		if old, ok := from[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = value
+		}
+	}
+	for _, key := range from.Keys() {
+		if _, ok := to[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return
+}
+
+// ApplyDiff applies a difference returned by DiffDataset.
+func (d Dataset) ApplyDiff(changed Dataset, removed []string) {
+	for _, key := range removed {
+		delete(d, key)
+	}
+	d.Merge(changed)
+}
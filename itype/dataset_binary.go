@@ -0,0 +1,219 @@
+package itype
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The binary encoding of a Dataset is:
+//
+//	uvarint     number of entries
+//	entries     in sorted key order, each:
+//	  uvarint   key length, then the key bytes
+//	  byte      type tag
+//	  value     varint (int), 8 bytes little-endian IEEE 754 (float64),
+//	            1 byte (bool), or uvarint length and bytes (string)
+//
+// Datasets are self-delimiting, so a stream is just one after another.
+const (
+	datasetTagInt    byte = 'i'
+	datasetTagFloat  byte = 'f'
+	datasetTagBool   byte = 'b'
+	datasetTagString byte = 's'
+)
+
+// datasetMaxLength limits the entry count and string lengths accepted when decoding.
+// Memory is only allocated as the data actually arrives, so a corrupt length cannot
+// allocate more than the input holds; datasetMaxPrealloc caps the entries made room for up front.
+const (
+	datasetMaxLength   = 1 << 24
+	datasetMaxPrealloc = 64
+)
+
+// appendBinary appends the binary encoding of the Dataset to buf.
+func (d Dataset) appendBinary(buf []byte) ([]byte, error) {
+	var tmp [binary.MaxVarintLen64]byte
+
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(d)))]...)
+	for _, key := range d.Keys() {
+		buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(key)))]...)
+		buf = append(buf, key...)
+
+		switch v := d[key].(type) {
+		case int:
+			buf = append(buf, datasetTagInt)
+			buf = append(buf, tmp[:binary.PutVarint(tmp[:], int64(v))]...)
+		case float64:
+			buf = append(buf, datasetTagFloat)
+			binary.LittleEndian.PutUint64(tmp[:8], math.Float64bits(v))
+			buf = append(buf, tmp[:8]...)
+		case bool:
+			buf = append(buf, datasetTagBool)
+			if v {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		case string:
+			buf = append(buf, datasetTagString)
+			buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(v)))]...)
+			buf = append(buf, v...)
+		default:
+			return nil, fmt.Errorf("itype: dataset key %q holds unsupported type %T", key, v)
+		}
+	}
+	return buf, nil
+}
+
+// MarshalBinary encodes the Dataset in the compact binary format.
+func (d Dataset) MarshalBinary() ([]byte, error) {
+	return d.appendBinary(nil)
+}
+
+// UnmarshalBinary decodes a Dataset in the binary format, replacing its content.
+func (d *Dataset) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	result, err := readDataset(r)
+	if err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("itype: %d extra bytes after dataset", r.Len())
+	}
+	*d = result
+	return nil
+}
+
+// datasetReader is what readDataset reads from.
+type datasetReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+func readLength(r datasetReader) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > datasetMaxLength {
+		return 0, fmt.Errorf("itype: dataset length %d too large", n)
+	}
+	return int(n), nil
+}
+
+func readString(r datasetReader) (string, error) {
+	n, err := readLength(r)
+	if err != nil {
+		return "", err
+	}
+	// Read in chunks as the data arrives, instead of trusting n for one allocation
+	var buf bytes.Buffer
+	if _, err = io.CopyN(&buf, r, int64(n)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// readDataset reads one Dataset in the binary format.
+// It returns io.EOF only if there is no data at all.
+func readDataset(r datasetReader) (d Dataset, err error) {
+	count, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any EOF from now on is in the middle of the Dataset
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	d = make(Dataset, minInt(count, datasetMaxPrealloc))
+	for i := 0; i < count; i++ {
+		key, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case datasetTagInt:
+			v, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			d[key] = int(v)
+		case datasetTagFloat:
+			var b [8]byte
+			if _, err = io.ReadFull(r, b[:]); err != nil {
+				return nil, err
+			}
+			d[key] = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+		case datasetTagBool:
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			d[key] = b != 0
+		case datasetTagString:
+			s, err := readString(r)
+			if err != nil {
+				return nil, err
+			}
+			d[key] = s
+		default:
+			return nil, fmt.Errorf("itype: unknown dataset type tag %q for key %q", tag, key)
+		}
+	}
+	return d, nil
+}
+
+// DatasetEncoder writes a stream of Datasets in the binary format.
+type DatasetEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewDatasetEncoder creates a DatasetEncoder writing to w.
+func NewDatasetEncoder(w io.Writer) *DatasetEncoder {
+	return &DatasetEncoder{w: w}
+}
+
+// Encode writes one Dataset to the stream.
+func (e *DatasetEncoder) Encode(d Dataset) (err error) {
+	e.buf, err = d.appendBinary(e.buf[:0])
+	if err != nil {
+		return
+	}
+	_, err = e.w.Write(e.buf)
+	return
+}
+
+// DatasetDecoder reads a stream of Datasets in the binary format.
+type DatasetDecoder struct {
+	r datasetReader
+}
+
+// NewDatasetDecoder creates a DatasetDecoder reading from r.
+//
+// If r is not an io.ByteReader it is buffered, and the decoder may read past the last Dataset.
+func NewDatasetDecoder(r io.Reader) *DatasetDecoder {
+	if dr, ok := r.(datasetReader); ok {
+		return &DatasetDecoder{r: dr}
+	}
+	return &DatasetDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next Dataset from the stream.
+// It returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF if it ends mid-Dataset.
+func (dec *DatasetDecoder) Decode() (Dataset, error) {
+	return readDataset(dec.r)
+}
@@ -0,0 +1,135 @@
+package itype
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"runtime"
+	"testing"
+)
+
+func testDatasets() []Dataset {
+	return []Dataset{
+		{},
+		{"int": 1, "neg": -300, "big": math.MaxInt64, "small": math.MinInt64},
+		{"float": 1.0, "frac": -0.25, "tiny": math.SmallestNonzeroFloat64, "inf": math.Inf(-1)},
+		{"t": true, "f": false, "empty": "", "s": "héllo\x00world", "": 0},
+	}
+}
+
+func TestDatasetBinaryRoundTrip(t *testing.T) {
+	for _, d := range testDatasets() {
+		data, err := d.MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary(%v) error: %v", d, err)
+			continue
+		}
+		var got Dataset
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Errorf("UnmarshalBinary(%v) error: %v", d, err)
+			continue
+		}
+		if !got.Equal(d) {
+			t.Errorf("binary round trip of %v = %v", d, got)
+		}
+	}
+
+	// NaN is kept, though it is not Equal to itself
+	data, _ := Dataset{"nan": math.NaN()}.MarshalBinary()
+	var got Dataset
+	if err := got.UnmarshalBinary(data); err != nil || !math.IsNaN(got["nan"].(float64)) {
+		t.Errorf("binary round trip of NaN = %v, %v", got, err)
+	}
+
+	if _, err := (Dataset{"k": int64(1)}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary() of an int64 succeeded")
+	}
+}
+
+func TestDatasetBinaryErrors(t *testing.T) {
+	data, _ := Dataset{"a": 1, "b": "text", "c": 2.5, "d": true}.MarshalBinary()
+
+	var d Dataset
+	if err := d.UnmarshalBinary(nil); err != io.EOF {
+		t.Errorf("UnmarshalBinary(empty) = %v, want io.EOF", err)
+	}
+	for n := 1; n < len(data); n++ {
+		if err := d.UnmarshalBinary(data[:n]); err != io.ErrUnexpectedEOF {
+			t.Errorf("UnmarshalBinary(first %d of %d bytes) = %v, want io.ErrUnexpectedEOF", n, len(data), err)
+		}
+	}
+	if err := d.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Error("UnmarshalBinary() with an extra byte succeeded")
+	}
+	if err := d.UnmarshalBinary([]byte{1, 1, 'k', 'x'}); err == nil {
+		t.Error("UnmarshalBinary() with an unknown tag succeeded")
+	}
+	if err := d.UnmarshalBinary([]byte{0x81, 0x80, 0x80, 0x08}); err == nil || err == io.ErrUnexpectedEOF {
+		t.Errorf("UnmarshalBinary() with a count over the limit = %v", err)
+	}
+}
+
+func TestDatasetBinaryHugeLength(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"count", []byte{0x80, 0x80, 0x80, 0x08}},                      // 1<<24 entries
+		{"key", []byte{1, 0x80, 0x80, 0x80, 0x08, 'k'}},                // one key of 1<<24 bytes
+		{"value", []byte{1, 1, 'k', 's', 0x80, 0x80, 0x80, 0x08, 'v'}}, // one string of 1<<24 bytes
+	}
+	for _, tt := range tests {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		var d Dataset
+		err := d.UnmarshalBinary(tt.data)
+		runtime.ReadMemStats(&after)
+
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: UnmarshalBinary() = %v, want io.ErrUnexpectedEOF", tt.name, err)
+		}
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+			t.Errorf("%s: UnmarshalBinary() of %d bytes allocated %d bytes", tt.name, len(tt.data), alloc)
+		}
+	}
+}
+
+func TestDatasetStream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewDatasetEncoder(&buf)
+	for _, d := range testDatasets() {
+		if err := enc.Encode(d); err != nil {
+			t.Fatalf("Encode(%v) error: %v", d, err)
+		}
+	}
+	stream := buf.Bytes()
+
+	readers := []struct {
+		name string
+		r    func(data []byte) io.Reader
+	}{
+		{"ByteReader", func(data []byte) io.Reader { return bytes.NewReader(data) }},
+		{"buffered", func(data []byte) io.Reader { return struct{ io.Reader }{bytes.NewReader(data)} }},
+	}
+	for _, rd := range readers {
+		dec := NewDatasetDecoder(rd.r(stream))
+		for i, want := range testDatasets() {
+			if got, err := dec.Decode(); err != nil || !got.Equal(want) {
+				t.Errorf("%s: Decode() #%d = %v, %v, want %v", rd.name, i, got, err, want)
+			}
+		}
+		if _, err := dec.Decode(); err != io.EOF {
+			t.Errorf("%s: Decode() at the end = %v, want io.EOF", rd.name, err)
+		}
+
+		// Cut off in the middle of the last Dataset
+		dec = NewDatasetDecoder(rd.r(stream[:len(stream)-1]))
+		var err error
+		for err == nil {
+			_, err = dec.Decode()
+		}
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: Decode() of a truncated stream = %v, want io.ErrUnexpectedEOF", rd.name, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package itype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MarshalJSON encodes the Dataset as a JSON object with sorted keys.
+//
+// float64 values are always written with a decimal point or an exponent (1 is "1.0"),
+// and ints never are, so UnmarshalJSON can tell them apart. NaN and infinities
+// cannot be encoded.
+func (d Dataset) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range d.Keys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		switch v := d[key].(type) {
+		case int:
+			buf.WriteString(strconv.Itoa(v))
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("itype: cannot encode %v in dataset key %q as JSON", v, key)
+			}
+			s := strconv.FormatFloat(v, 'g', -1, 64)
+			if !strings.ContainsAny(s, ".e") {
+				s += ".0"
+			}
+			buf.WriteString(s)
+		case bool:
+			buf.WriteString(strconv.FormatBool(v))
+		case string:
+			s, _ := json.Marshal(v)
+			buf.Write(s)
+		default:
+			return nil, fmt.Errorf("itype: dataset key %q holds unsupported type %T", key, v)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object into the Dataset, replacing its content.
+//
+// Numbers with a decimal point or an exponent become float64, and the others int.
+// Values other than numbers, booleans and strings are an error.
+func (d *Dataset) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+	if m == nil {
+		*d = nil
+		return nil
+	}
+
+	result := make(Dataset, len(m))
+	for key, value := range m {
+		switch v := value.(type) {
+		case json.Number:
+			s := string(v)
+			if strings.ContainsAny(s, ".eE") {
+				f, err := v.Float64()
+				if err != nil {
+					return fmt.Errorf("itype: dataset key %q: %w", key, err)
+				}
+				result[key] = f
+			} else {
+				i, err := strconv.Atoi(s)
+				if err != nil {
+					return fmt.Errorf("itype: dataset key %q: %w", key, err)
+				}
+				result[key] = i
+			}
+		case bool, string:
+			result[key] = v
+		default:
+			return fmt.Errorf("itype: dataset key %q holds unsupported JSON value %v", key, v)
+		}
+	}
+	*d = result
+	return nil
+}
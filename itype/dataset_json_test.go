@@ -0,0 +1,59 @@
+package itype
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestDatasetJSONRoundTrip(t *testing.T) {
+	for _, d := range testDatasets() {
+		if _, ok := d["inf"]; ok {
+			continue // Not representable in JSON
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Errorf("Marshal(%v) error: %v", d, err)
+			continue
+		}
+		var got Dataset
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", data, err)
+			continue
+		}
+		if !got.Equal(d) {
+			t.Errorf("JSON round trip of %v through %s = %v", d, data, got)
+		}
+	}
+}
+
+func TestDatasetJSON(t *testing.T) {
+	data, err := json.Marshal(Dataset{"b": 1.0, "a": 1, "c": 1e21, "d": "x"})
+	if want := `{"a":1,"b":1.0,"c":1e+21,"d":"x"}`; err != nil || string(data) != want {
+		t.Errorf("Marshal() = %s, %v, want %s", data, err, want)
+	}
+	if data, err := json.Marshal(Dataset(nil)); err != nil || string(data) != "null" {
+		t.Errorf("Marshal(nil) = %s, %v", data, err)
+	}
+	for _, v := range []float64{math.NaN(), math.Inf(1)} {
+		if _, err := json.Marshal(Dataset{"k": v}); err == nil {
+			t.Errorf("Marshal(%v) succeeded", v)
+		}
+	}
+
+	var d Dataset
+	if err := json.Unmarshal([]byte(`{"i": 2, "f": 2.0, "e": 2e0, "s": "2", "b": true}`), &d); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if want := (Dataset{"i": 2, "f": 2.0, "e": 2.0, "s": "2", "b": true}); !d.Equal(want) {
+		t.Errorf("Unmarshal() = %v, want %v", d, want)
+	}
+	if err := json.Unmarshal([]byte(`null`), &d); err != nil || d != nil {
+		t.Errorf("Unmarshal(null) = %v, %v", d, err)
+	}
+	for _, in := range []string{`{"k": null}`, `{"k": [1]}`, `{"k": {}}`, `{"k": 99999999999999999999}`, `[1]`} {
+		if err := json.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", in)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package itype
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDatasetCoercion(t *testing.T) {
+	d := Dataset{
+		"int":      3,
+		"float":    2.5,
+		"whole":    4.0,
+		"negative": -7.0,
+		"huge":     1e300,
+		"edge":     float64(-math.MinInt), // 2^63, just out of range
+		"nan":      math.NaN(),
+		"inf":      math.Inf(1),
+		"bool":     true,
+		"string":   "5",
+	}
+
+	intTests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"int", 3, true},
+		{"whole", 4, true},
+		{"negative", -7, true},
+		{"float", 0, false},
+		{"huge", 0, false},
+		{"edge", 0, false},
+		{"nan", 0, false},
+		{"inf", 0, false},
+		{"bool", 0, false},
+		{"string", 0, false},
+		{"missing", 0, false},
+	}
+	for _, tt := range intTests {
+		if v, ok := d.GetInt(tt.key); v != tt.want || ok != tt.ok {
+			t.Errorf("GetInt(%q) = %v, %v, want %v, %v", tt.key, v, ok, tt.want, tt.ok)
+		}
+	}
+	if v, ok := (Dataset{"min": float64(math.MinInt)}).GetInt("min"); v != math.MinInt || !ok {
+		t.Errorf("GetInt(MinInt) = %v, %v", v, ok)
+	}
+
+	floatTests := []struct {
+		key  string
+		want float64
+		ok   bool
+	}{
+		{"float", 2.5, true},
+		{"int", 3, true},
+		{"bool", 0, false},
+		{"string", 0, false},
+		{"missing", 0, false},
+	}
+	for _, tt := range floatTests {
+		if v, ok := d.GetFloat(tt.key); v != tt.want || ok != tt.ok {
+			t.Errorf("GetFloat(%q) = %v, %v, want %v, %v", tt.key, v, ok, tt.want, tt.ok)
+		}
+	}
+
+	if v, ok := d.GetBool("bool"); !v || !ok {
+		t.Errorf("GetBool(bool) = %v, %v", v, ok)
+	}
+	if _, ok := d.GetBool("int"); ok {
+		t.Error("GetBool(int) ok")
+	}
+	if v, ok := d.GetString("string"); v != "5" || !ok {
+		t.Errorf("GetString(string) = %q, %v", v, ok)
+	}
+	if _, ok := d.GetString("int"); ok {
+		t.Error("GetString(int) ok")
+	}
+}
+
+func TestDatasetOr(t *testing.T) {
+	d := Dataset{"i": 1, "f": 2.0, "b": false, "s": "x"}
+	runOpCases(t, []opCase{
+		{"IntOr", d.IntOr("i", 9), 1},
+		{"IntOr coerced", d.IntOr("f", 9), 2},
+		{"IntOr default", d.IntOr("s", 9), 9},
+		{"FloatOr", d.FloatOr("f", 9), 2.0},
+		{"FloatOr coerced", d.FloatOr("i", 9), 1.0},
+		{"FloatOr default", d.FloatOr("missing", 9), 9.0},
+		{"BoolOr", d.BoolOr("b", true), false},
+		{"BoolOr default", d.BoolOr("i", true), true},
+		{"StringOr", d.StringOr("s", "y"), "x"},
+		{"StringOr default", d.StringOr("b", "y"), "y"},
+	})
+}
+
+func TestDatasetSet(t *testing.T) {
+	d := Dataset{}
+	for _, v := range []interface{}{1, 1.5, true, "s"} {
+		if err := d.Set("k", v); err != nil || d["k"] != v {
+			t.Errorf("Set(%T) = %v, stored %v", v, err, d["k"])
+		}
+	}
+	for _, v := range []interface{}{int64(1), float32(1), nil, []int{1}} {
+		if err := d.Set("k", v); err == nil {
+			t.Errorf("Set(%T) succeeded", v)
+		}
+	}
+	if d["k"] != "s" {
+		t.Errorf("failed Set changed the value to %v", d["k"])
+	}
+
+	if err := (Dataset{"a": 1, "b": "x"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	if err := (Dataset{"a": 1, "b": uint8(2), "c": nil}).Validate(); err == nil {
+		t.Error("Validate() of bad values = nil")
+	}
+}
+
+func TestDatasetKeys(t *testing.T) {
+	d := Dataset{"b": 1, "c": 2, "a": 3}
+	if keys := d.Keys(); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+	if keys := (Dataset{}).Keys(); len(keys) != 0 {
+		t.Errorf("Keys() of empty = %v", keys)
+	}
+
+	var visited []string
+	d.Range(func(key string, value interface{}) bool {
+		visited = append(visited, key)
+		return key != "b"
+	})
+	if !reflect.DeepEqual(visited, []string{"a", "b"}) {
+		t.Errorf("Range() stopping at b visited %v", visited)
+	}
+}
+
+func TestDatasetCopyEqual(t *testing.T) {
+	d := Dataset{"i": 1, "f": 1.5, "b": true, "s": "x"}
+	c := d.Copy()
+	if !d.Equal(c) || !c.Equal(d) {
+		t.Fatalf("Copy() = %v, not equal to %v", c, d)
+	}
+	c["i"] = 2
+	if d["i"] != 1 {
+		t.Error("changing the copy changed the original")
+	}
+	if d.Equal(c) {
+		t.Error("Equal() after a change")
+	}
+	if (Dataset(nil)).Copy() != nil {
+		t.Error("Copy() of nil is not nil")
+	}
+
+	tests := []struct {
+		name string
+		a, b Dataset
+		want bool
+	}{
+		{"nil and empty", nil, Dataset{}, true},
+		{"int and float", Dataset{"k": 1}, Dataset{"k": 1.0}, false},
+		{"missing key", Dataset{"k": 1}, Dataset{"j": 1}, false},
+		{"extra key", Dataset{"k": 1}, Dataset{"k": 1, "j": 1}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Equal(tt.b); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDatasetDiff(t *testing.T) {
+	from := Dataset{"same": 1, "changed": "a", "retyped": 2, "gone": true, "gone2": 0.5}
+	to := Dataset{"same": 1, "changed": "b", "retyped": 2.0, "new": 3}
+
+	changed, removed := DiffDataset(from, to)
+	if want := (Dataset{"changed": "b", "retyped": 2.0, "new": 3}); !changed.Equal(want) {
+		t.Errorf("DiffDataset() changed = %v, want %v", changed, want)
+	}
+	if want := []string{"gone", "gone2"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("DiffDataset() removed = %v, want %v", removed, want)
+	}
+
+	applied := from.Copy()
+	applied.ApplyDiff(changed, removed)
+	if !applied.Equal(to) {
+		t.Errorf("ApplyDiff() = %v, want %v", applied, to)
+	}
+
+	if changed, removed := DiffDataset(to, to.Copy()); len(changed) != 0 || len(removed) != 0 {
+		t.Errorf("DiffDataset() of equal = %v, %v", changed, removed)
+	}
+
+	merged := Dataset{"a": 1, "b": 2}
+	merged.Merge(Dataset{"b": "x", "c": 3})
+	if want := (Dataset{"a": 1, "b": "x", "c": 3}); !merged.Equal(want) {
+		t.Errorf("Merge() = %v, want %v", merged, want)
+	}
+}
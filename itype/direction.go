@@ -1,5 +1,13 @@
 package itype
 
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Direction is one of the six axis-aligned directions.
+// Each positive direction is immediately followed by its negative one.
 type Direction int
 
 const (
@@ -35,3 +43,138 @@ var DirectionVecd = [6]Vec3d{
 	ZPlus:  {0, 0, 1},
 	ZMinus: {0, 0, -1},
 }
+
+var directionNames = [6]string{
+	XPlus:  "X+",
+	XMinus: "X-",
+	YPlus:  "Y+",
+	YMinus: "Y-",
+	ZPlus:  "Z+",
+	ZMinus: "Z-",
+}
+
+// Opposite returns the direction pointing the other way.
+func (d Direction) Opposite() Direction {
+	return d ^ 1
+}
+
+// Axis returns the index of the axis of the direction: 0 for X, 1 for Y and 2 for Z.
+func (d Direction) Axis() int {
+	return int(d) / 2
+}
+
+// Positive returns true for XPlus, YPlus and ZPlus.
+func (d Direction) Positive() bool {
+	return d%2 == 0
+}
+
+// Negative returns true for XMinus, YMinus and ZMinus.
+func (d Direction) Negative() bool {
+	return d%2 == 1
+}
+
+// String returns the name of the direction, like "X+".
+func (d Direction) String() string {
+	if d < 0 || d > ZMinus {
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+	return directionNames[d]
+}
+
+// ParseDirection parses a direction name, like "X+", "+x" or "y-".
+func ParseDirection(s string) (Direction, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	if len(str) == 2 && (str[0] == '+' || str[0] == '-') {
+		str = str[1:] + str[:1]
+	}
+	for d, name := range directionNames {
+		if str == name {
+			return Direction(d), nil
+		}
+	}
+	return 0, fmt.Errorf("itype: invalid direction %q", s)
+}
+
+// DirectionFromAxis returns the direction along the axis (0, 1 or 2 for X, Y, Z), positive or not.
+func DirectionFromAxis(axis int, positive bool) Direction {
+	if positive {
+		return Direction(axis * 2)
+	}
+	return Direction(axis*2 + 1)
+}
+
+// NearestDirection returns the direction closest to the vector,
+// that is along its component of the largest magnitude.
+// Ties go to the first axis; the zero vector gives XPlus.
+func NearestDirection(v Vec3d) Direction {
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(v[i]) > math.Abs(v[axis]) {
+			axis = i
+		}
+	}
+	return DirectionFromAxis(axis, v[axis] >= 0)
+}
+
+// Rotate rotates the direction by quarter turns around the axis direction,
+// counter-clockwise when looking from the axis towards the origin (right-handed).
+// Negative turns rotate clockwise.
+func (d Direction) Rotate(axis Direction, turns int) Direction {
+	a, v := DirectionVeci[axis], DirectionVeci[d]
+	turns = ((turns % 4) + 4) % 4
+	for i := 0; i < turns; i++ {
+		// a x v + (a . v) a, for a quarter turn of v around the unit vector a
+		v = a.Cross(v).Add(a.MultiplyInt(a.Dot(v)))
+	}
+	return NearestDirection(v.ToFloat64())
+}
+
+// Neighbor returns the position next to p in the direction.
+func (p Vec3i) Neighbor(d Direction) Vec3i {
+	return p.Add(DirectionVeci[d])
+}
+
+// Neighbors6 returns the six positions sharing a face with p, in Direction order.
+func Neighbors6(p Vec3i) (n [6]Vec3i) {
+	for d := XPlus; d <= ZMinus; d++ {
+		n[d] = p.Add(DirectionVeci[d])
+	}
+	return
+}
+
+// Neighbors26 returns the 26 positions sharing a face, an edge or a corner with p,
+// ordered by x, then y, then z offset from -1 to 1.
+func Neighbors26(p Vec3i) (n [26]Vec3i) {
+	i := 0
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			for z := -1; z <= 1; z++ {
+				if x != 0 || y != 0 || z != 0 {
+					n[i] = p.Addv(x, y, z)
+					i++
+				}
+			}
+		}
+	}
+	return
+}
+
+// EachNeighbor6 calls f with every face neighbor of p and its direction from p,
+// until f returns false.
+func EachNeighbor6(p Vec3i, f func(n Vec3i, d Direction) bool) {
+	for d := XPlus; d <= ZMinus; d++ {
+		if !f(p.Add(DirectionVeci[d]), d) {
+			return
+		}
+	}
+}
+
+// EachNeighbor26 calls f with every neighbor of p and its offset from p,
+// in the order of Neighbors26, until f returns false.
+func EachNeighbor26(p Vec3i, f func(n, offset Vec3i) bool) {
+	for _, n := range Neighbors26(p) {
+		if !f(n, n.Sub(p)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package itype
+
+import (
+	"testing"
+)
+
+func TestDirectionRotate(t *testing.T) {
+	runOpCases(t, []opCase{
+		// Counter-clockwise looking from the axis towards the origin
+		{"XPlus.Rotate(ZPlus, 1)", XPlus.Rotate(ZPlus, 1), YPlus},
+		{"YPlus.Rotate(ZPlus, 1)", YPlus.Rotate(ZPlus, 1), XMinus},
+		{"YPlus.Rotate(XPlus, 1)", YPlus.Rotate(XPlus, 1), ZPlus},
+		{"ZPlus.Rotate(YPlus, 1)", ZPlus.Rotate(YPlus, 1), XPlus},
+		{"XPlus.Rotate(ZMinus, 1)", XPlus.Rotate(ZMinus, 1), YMinus},
+		{"XPlus.Rotate(ZPlus, -1)", XPlus.Rotate(ZPlus, -1), YMinus},
+		{"XPlus.Rotate(ZPlus, 2)", XPlus.Rotate(ZPlus, 2), XMinus},
+		{"XPlus.Rotate(ZPlus, 5)", XPlus.Rotate(ZPlus, 5), YPlus},
+		{"ZPlus.Rotate(ZPlus, 1)", ZPlus.Rotate(ZPlus, 1), ZPlus},
+		{"ZMinus.Rotate(ZPlus, 3)", ZMinus.Rotate(ZPlus, 3), ZMinus},
+	})
+
+	for axis := XPlus; axis <= ZMinus; axis++ {
+		for d := XPlus; d <= ZMinus; d++ {
+			r := d
+			for i := 0; i < 4; i++ {
+				r = r.Rotate(axis, 1)
+			}
+			if r != d {
+				t.Errorf("%v rotated four quarter turns around %v = %v", d, axis, r)
+			}
+			if back := d.Rotate(axis, 1).Rotate(axis, -1); back != d {
+				t.Errorf("%v rotated a quarter turn and back around %v = %v", d, axis, back)
+			}
+		}
+	}
+}
+
+func TestNearestDirection(t *testing.T) {
+	runOpCases(t, []opCase{
+		{"NearestDirection(X+)", NearestDirection(Vec3d{2, 1, -1}), XPlus},
+		{"NearestDirection(X-)", NearestDirection(Vec3d{-2, 1, 1}), XMinus},
+		{"NearestDirection(Y+)", NearestDirection(Vec3d{0.1, 0.5, -0.2}), YPlus},
+		{"NearestDirection(Z-)", NearestDirection(Vec3d{1, -1, -3}), ZMinus},
+		{"NearestDirection(tie)", NearestDirection(Vec3d{1, -1, 1}), XPlus},
+		{"NearestDirection(tie Y Z)", NearestDirection(Vec3d{0, -1, 1}), YMinus},
+		{"NearestDirection(zero)", NearestDirection(Vec3d{}), XPlus},
+		{"NearestDirection(-0)", NearestDirection(Vec3d{0, 0, -0.5}), ZMinus},
+	})
+	for d := XPlus; d <= ZMinus; d++ {
+		if got := NearestDirection(DirectionVecd[d]); got != d {
+			t.Errorf("NearestDirection(%v) = %v, want %v", DirectionVecd[d], got, d)
+		}
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Direction
+		ok   bool
+	}{
+		{"X+", XPlus, true},
+		{"x+", XPlus, true},
+		{"+x", XPlus, true},
+		{"+X", XPlus, true},
+		{"-y", YMinus, true},
+		{" z- ", ZMinus, true},
+		{"x", 0, false},
+		{"+", 0, false},
+		{"x++", 0, false},
+		{"w+", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		d, err := ParseDirection(tt.s)
+		if (err == nil) != tt.ok || (tt.ok && d != tt.want) {
+			t.Errorf("ParseDirection(%q) = %v, %v, want %v", tt.s, d, err, tt.want)
+		}
+	}
+
+	for d := XPlus; d <= ZMinus; d++ {
+		if got, err := ParseDirection(d.String()); err != nil || got != d {
+			t.Errorf("ParseDirection(%q) = %v, %v, want %v", d.String(), got, err, d)
+		}
+	}
+	if s := Direction(6).String(); s != "Direction(6)" {
+		t.Errorf("Direction(6).String() = %q", s)
+	}
+}
+
+func TestNeighbors26(t *testing.T) {
+	p := Vec3i{10, -5, 3}
+	n := Neighbors26(p)
+
+	seen := make(map[Vec3i]bool)
+	for i, q := range n {
+		off := q.Sub(p)
+		if off == (Vec3i{}) || off.Abs().Max(Vec3i{1, 1, 1}) != (Vec3i{1, 1, 1}) {
+			t.Errorf("Neighbors26()[%d] = %v, offset %v", i, q, off)
+		}
+		if seen[q] {
+			t.Errorf("Neighbors26()[%d] = %v repeated", i, q)
+		}
+		seen[q] = true
+	}
+	runOpCases(t, []opCase{
+		{"Neighbors26 first", n[0], Vec3i{9, -6, 2}},
+		{"Neighbors26 before the center", n[12], Vec3i{10, -5, 2}},
+		{"Neighbors26 after the center", n[13], Vec3i{10, -5, 4}},
+		{"Neighbors26 last", n[25], Vec3i{11, -4, 4}},
+	})
+
+	i := 0
+	EachNeighbor26(p, func(q, offset Vec3i) bool {
+		if q != n[i] || offset != q.Sub(p) {
+			t.Errorf("EachNeighbor26 #%d = %v, %v, want %v", i, q, offset, n[i])
+		}
+		i++
+		return true
+	})
+	if i != 26 {
+		t.Errorf("EachNeighbor26 visited %d neighbors", i)
+	}
+
+	i = 0
+	EachNeighbor26(p, func(q, offset Vec3i) bool {
+		i++
+		return i < 5
+	})
+	if i != 5 {
+		t.Errorf("EachNeighbor26 returning false at 5 visited %d neighbors", i)
+	}
+}
@@ -0,0 +1,125 @@
+// Package imguiconv converts between the itype math types and the imgui and ImPlot
+// types, plots itype vectors with ImPlot, and reads the geometry of the current plot.
+//
+// It is kept apart from itype so that itype does not depend on the cgo imgui bindings.
+package imguiconv
+
+import (
+	"unsafe"
+
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/itype"
+)
+
+// RectfFromClipRect converts an imgui clip rectangle, as returned by DrawCommand.ClipRect,
+// with (X, Y) the top-left and (Z, W) the bottom-right corner.
+func RectfFromClipRect(clip imgui.Vec4) itype.Rectf {
+	return itype.Rectf{Left: clip.X, Top: clip.Y, Width: clip.Z - clip.X, Height: clip.W - clip.Y}
+}
+
+// ClipRect converts the rectangle into an imgui clip rectangle.
+func ClipRect(r itype.Rectf) imgui.Vec4 {
+	return imgui.Vec4{X: r.Left, Y: r.Top, Z: r.Left + r.Width, W: r.Top + r.Height}
+}
+
+// RectdFromPlotRect converts ImPlot plot limits, with the X range
+// becoming Left/Width and the Y range Top/Height.
+func RectdFromPlotRect(limits imgui.Rect) itype.Rectd {
+	return itype.Rectd{
+		Left:   limits.X.Min,
+		Top:    limits.Y.Min,
+		Width:  limits.X.Max - limits.X.Min,
+		Height: limits.Y.Max - limits.Y.Min,
+	}
+}
+
+// PlotRect converts the rectangle into ImPlot plot limits.
+// Use it with imgui.SetupAxesLimits as SetupAxesLimits(p.X.Min, p.X.Max, p.Y.Min, p.Y.Max, cond).
+func PlotRect(r itype.Rectd) imgui.Rect {
+	return imgui.RectFromAABB(r.Left, r.Left+r.Width, r.Top, r.Top+r.Height)
+}
+
+// Vec2 converts the vector to an imgui.Vec2.
+func Vec2(v itype.Vec2f) imgui.Vec2 {
+	return imgui.Vec2{X: v[0], Y: v[1]}
+}
+
+// Vec2fFromImgui converts an imgui.Vec2.
+func Vec2fFromImgui(v imgui.Vec2) itype.Vec2f {
+	return itype.Vec2f{v.X, v.Y}
+}
+
+// Vec4 converts the vector to an imgui.Vec4.
+func Vec4(v itype.Vec4f) imgui.Vec4 {
+	return imgui.Vec4{X: v[0], Y: v[1], Z: v[2], W: v[3]}
+}
+
+// Vec4fFromImgui converts an imgui.Vec4.
+func Vec4fFromImgui(v imgui.Vec4) itype.Vec4f {
+	return itype.Vec4f{v.X, v.Y, v.Z, v.W}
+}
+
+// Packed converts the vector, as RGBA color components in [0, 1], to an imgui.PackedColor.
+func Packed(v itype.Vec4f) imgui.PackedColor {
+	return imgui.PackedColorFromVec4(Vec4(v))
+}
+
+// Vec4fFromPacked converts an imgui.PackedColor to RGBA color components in [0, 1].
+func Vec4fFromPacked(c imgui.PackedColor) itype.Vec4f {
+	return itype.Vec4f{
+		float32(c&0xff) / 255,
+		float32(c>>8&0xff) / 255,
+		float32(c>>16&0xff) / 255,
+		float32(c>>24&0xff) / 255,
+	}
+}
+
+// Point converts the vector to an ImPlot point.
+func Point(v itype.Vec2d) imgui.Point {
+	return imgui.Point{X: v[0], Y: v[1]}
+}
+
+// Vec2dFromPoint converts an ImPlot point.
+func Vec2dFromPoint(p imgui.Point) itype.Vec2d {
+	return itype.Vec2d{p.X, p.Y}
+}
+
+// Points returns the vectors as a slice of ImPlot points.
+//
+// Vec2d and imgui.Point have the same memory layout, so no copy is made:
+// the two slices share their elements.
+func Points(v []itype.Vec2d) []imgui.Point {
+	if len(v) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*imgui.Point)(unsafe.Pointer(&v[0])), len(v))
+}
+
+// Vec2dFromPoints returns the ImPlot points as a slice of vectors, sharing the elements like Points.
+func Vec2dFromPoints(p []imgui.Point) []itype.Vec2d {
+	if len(p) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*itype.Vec2d)(unsafe.Pointer(&p[0])), len(p))
+}
+
+// PlotLineP plots a line through the points, like imgui.PlotLineP.
+func PlotLineP(label string, points []itype.Vec2d) {
+	imgui.PlotLineP(label, Points(points))
+}
+
+// PlotScatterP plots the points, like imgui.PlotScatterP.
+func PlotScatterP(label string, points []itype.Vec2d) {
+	imgui.PlotScatterP(label, Points(points))
+}
+
+// PlotStairsP plots a stairstep line through the points, like imgui.PlotStairsP.
+func PlotStairsP(label string, points []itype.Vec2d) {
+	imgui.PlotStairsP(label, Points(points))
+}
+
+// PlotShadedRefP plots the region between the points and the horizontal line at yref,
+// like imgui.PlotShadedRefP.
+func PlotShadedRefP(label string, points []itype.Vec2d, yref float64) {
+	imgui.PlotShadedRefP(label, Points(points), yref)
+}
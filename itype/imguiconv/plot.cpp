@@ -0,0 +1,34 @@
+#include "plot.h"
+
+// The imgui-go module compiles ImPlot into the binary, but neither exports its
+// headers to other packages nor wraps these queries, so the declarations are
+// repeated here. They must match implot.h (version 0.13) in layout and signature.
+struct ImVec2 {
+	float x, y;
+};
+struct ImPlotRange {
+	double Min, Max;
+};
+struct ImPlotRect {
+	ImPlotRange X, Y;
+};
+typedef int ImAxis;
+
+namespace ImPlot {
+ImVec2 GetPlotPos();
+ImVec2 GetPlotSize();
+ImPlotRect GetPlotLimits(ImAxis x_axis, ImAxis y_axis);
+} // namespace ImPlot
+
+void imguiconvPlotGeometry(float *area, double *limits) {
+	ImVec2 pos = ImPlot::GetPlotPos(), size = ImPlot::GetPlotSize();
+	ImPlotRect rect = ImPlot::GetPlotLimits(-1, -1); // IMPLOT_AUTO, the current axes
+	area[0] = pos.x;
+	area[1] = pos.y;
+	area[2] = size.x;
+	area[3] = size.y;
+	limits[0] = rect.X.Min;
+	limits[1] = rect.X.Max;
+	limits[2] = rect.Y.Min;
+	limits[3] = rect.Y.Max;
+}
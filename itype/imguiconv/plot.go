@@ -0,0 +1,32 @@
+package imguiconv
+
+// #cgo CXXFLAGS: -std=c++11
+// #include "plot.h"
+import "C"
+
+import (
+	"github.com/Edgaru089/implot-go-example/itype"
+)
+
+// CurrentPlotMapping returns the mapping between plot coordinates and pixels of the
+// current ImPlot plot, with its real plot area and axis limits.
+//
+// It must be called between BeginPlot and EndPlot, after the setup is done
+// (after imgui.SetupFinish, or after the first item is plotted).
+// The scales must match the axis flags the plot was set up with.
+func CurrentPlotMapping(xScale, yScale itype.AxisScale) itype.PlotMapping {
+	var area [4]C.float
+	var limits [4]C.double
+	C.imguiconvPlotGeometry(&area[0], &limits[0])
+	return itype.PlotMapping{
+		Limits: itype.Rectd{
+			Left:   float64(limits[0]),
+			Top:    float64(limits[2]),
+			Width:  float64(limits[1] - limits[0]),
+			Height: float64(limits[3] - limits[2]),
+		},
+		Area:   itype.Rectd{Left: float64(area[0]), Top: float64(area[1]), Width: float64(area[2]), Height: float64(area[3])},
+		XScale: xScale,
+		YScale: yScale,
+	}
+}
@@ -0,0 +1,13 @@
+#pragma once
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// imguiconvPlotGeometry returns the plot area of the current ImPlot plot in pixels
+// (area[0..3]: x, y, width, height) and its axis limits (limits[0..3]: x min, x max, y min, y max).
+void imguiconvPlotGeometry(float *area, double *limits);
+
+#ifdef __cplusplus
+}
+#endif
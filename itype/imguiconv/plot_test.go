@@ -0,0 +1,48 @@
+package imguiconv
+
+import (
+	"testing"
+
+	"github.com/Edgaru089/imgui-go/v4"
+	"github.com/Edgaru089/implot-go-example/itype"
+)
+
+func TestCurrentPlotMapping(t *testing.T) {
+	ctx := imgui.CreateContext(nil)
+	defer ctx.Destroy()
+	io := imgui.CurrentIO()
+	io.SetIniFilename("")
+	io.SetDisplaySize(imgui.Vec2{X: 800, Y: 600})
+	io.SetDeltaTime(1.0 / 60)
+	io.Fonts().TextureDataRGBA32()
+
+	imgui.NewFrame()
+	imgui.Begin("Test")
+	var mapping itype.PlotMapping
+	var frameMin, frameMax imgui.Vec2
+	if imgui.BeginPlotV("Plot", imgui.Vec2{X: 400, Y: 300}, 0) {
+		imgui.SetupAxes("x", "y", imgui.AxisFlags_LogScale, 0)
+		imgui.SetupAxesLimits(1, 1000, -2, 2, imgui.Cond(imgui.ConditionAlways))
+		imgui.SetupFinish()
+		mapping = CurrentPlotMapping(itype.ScaleLog10, itype.ScaleLinear)
+		imgui.EndPlot()
+		frameMin, frameMax = imgui.ItemRectMin(), imgui.ItemRectMax()
+	}
+	imgui.End()
+	imgui.Render()
+
+	if want := (itype.Rectd{Left: 1, Top: -2, Width: 999, Height: 4}); mapping.Limits != want {
+		t.Errorf("Limits = %v, want %v", mapping.Limits, want)
+	}
+	if mapping.XScale != itype.ScaleLog10 || mapping.YScale != itype.ScaleLinear {
+		t.Errorf("scales = %v, %v", mapping.XScale, mapping.YScale)
+	}
+
+	// The plot area is inside the frame, which also holds the title and the axis labels
+	area := mapping.Area
+	if area.Width <= 0 || area.Height <= 0 ||
+		area.Left <= float64(frameMin.X) || area.Top <= float64(frameMin.Y) ||
+		area.Left+area.Width >= float64(frameMax.X) || area.Top+area.Height >= float64(frameMax.Y) {
+		t.Errorf("Area = %v, not inside the frame %v-%v", area, frameMin, frameMax)
+	}
+}
@@ -0,0 +1,270 @@
+package itype
+
+import (
+	"math"
+)
+
+// AxisScale is how a plot axis maps values to positions.
+type AxisScale int
+
+const (
+	ScaleLinear AxisScale = iota // Evenly spaced values
+	ScaleLog10                   // Logarithmic, like ImPlot's AxisFlags_LogScale
+)
+
+// transform maps a value to the space where the axis is linear.
+// ok is false for values that cannot be shown, like non-positive ones on a log axis.
+func (s AxisScale) transform(v float64) (t float64, ok bool) {
+	if s == ScaleLog10 {
+		if v <= 0 {
+			return 0, false
+		}
+		return math.Log10(v), true
+	}
+	return v, true
+}
+
+// inverse maps a value back from transform.
+func (s AxisScale) inverse(t float64) float64 {
+	if s == ScaleLog10 {
+		return math.Pow(10, t)
+	}
+	return t
+}
+
+// KDTree is a 2D k-d tree over points, for nearest neighbor and radius queries.
+//
+// The points are indexed as they appear on axes with the given scales:
+// distances are measured after the log transform of log axes, with a weight
+// on each axis (usually pixels per unit, from PlotMapping.Weight) so they can be in pixels.
+// Points that cannot appear on a log axis are left out.
+type KDTree struct {
+	scale [2]AxisScale
+	pts   []Vec2d // transformed points, in tree order
+	index []int   // index of each point in the original slice
+}
+
+// NewKDTree builds a KDTree over the points, in O(n log n).
+// The points are copied, so the slice can be changed afterwards.
+func NewKDTree(points []Vec2d, xScale, yScale AxisScale) *KDTree {
+	t := &KDTree{
+		scale: [2]AxisScale{xScale, yScale},
+		pts:   make([]Vec2d, 0, len(points)),
+		index: make([]int, 0, len(points)),
+	}
+	for i, p := range points {
+		x, okx := xScale.transform(p[0])
+		y, oky := yScale.transform(p[1])
+		if okx && oky && !math.IsNaN(x) && !math.IsNaN(y) {
+			t.pts = append(t.pts, Vec2d{x, y})
+			t.index = append(t.index, i)
+		}
+	}
+	t.build(0, len(t.pts), 0)
+	return t
+}
+
+// Len returns the number of points in the tree.
+func (t *KDTree) Len() int {
+	return len(t.pts)
+}
+
+// Scales returns the axis scales the tree was built with.
+func (t *KDTree) Scales() (x, y AxisScale) {
+	return t.scale[0], t.scale[1]
+}
+
+func (t *KDTree) swap(i, j int) {
+	t.pts[i], t.pts[j] = t.pts[j], t.pts[i]
+	t.index[i], t.index[j] = t.index[j], t.index[i]
+}
+
+// build arranges [lo, hi) so that the median along axis is in the middle,
+// with smaller points before it and larger after, and recurses on both halves.
+func (t *KDTree) build(lo, hi, axis int) {
+	if hi-lo <= 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	t.selectNth(lo, hi, mid, axis)
+	t.build(lo, mid, axis^1)
+	t.build(mid+1, hi, axis^1)
+}
+
+// selectNth partially sorts [lo, hi) along axis so that n holds the element it would in sorted order
+// (quickselect with median-of-three pivots and a three-way partition, so that runs of
+// equal coordinates, common in quantized data, split evenly instead of taking quadratic time).
+func (t *KDTree) selectNth(lo, hi, n, axis int) {
+	for hi-lo > 1 {
+		pivot := median3(t.pts[lo][axis], t.pts[(lo+hi)/2][axis], t.pts[hi-1][axis])
+
+		// Partition into [lo, lt) < pivot == [lt, gt) < [gt, hi)
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch v := t.pts[i][axis]; {
+			case v < pivot:
+				t.swap(lt, i)
+				lt++
+				i++
+			case v > pivot:
+				gt--
+				t.swap(i, gt)
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case n < lt:
+			hi = lt
+		case n >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// median3 returns the median of three values.
+func median3(a, b, c float64) float64 {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b = c
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// transformQuery transforms a query point like the tree points.
+func (t *KDTree) transformQuery(p Vec2d) (Vec2d, bool) {
+	x, okx := t.scale[0].transform(p[0])
+	y, oky := t.scale[1].transform(p[1])
+	return Vec2d{x, y}, okx && oky
+}
+
+// Nearest returns the index (in the slice given to NewKDTree) of the point closest to p,
+// and its weighted distance. Only points closer than maxDist are considered;
+// index is -1 if there are none.
+func (t *KDTree) Nearest(p Vec2d, weight Vec2d, maxDist float64) (index int, dist float64) {
+	q, ok := t.transformQuery(p)
+	if !ok {
+		return -1, 0
+	}
+
+	best, bestDist2 := -1, maxDist*maxDist
+	var search func(lo, hi, axis int)
+	search = func(lo, hi, axis int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		pt := t.pts[mid]
+		dx, dy := (pt[0]-q[0])*weight[0], (pt[1]-q[1])*weight[1]
+		if d2 := dx*dx + dy*dy; d2 < bestDist2 {
+			best, bestDist2 = mid, d2
+		}
+
+		// Search the side of the query point first, and the other only if it can be closer
+		split := (q[axis] - pt[axis]) * weight[axis]
+		if split < 0 {
+			search(lo, mid, axis^1)
+			if split*split < bestDist2 {
+				search(mid+1, hi, axis^1)
+			}
+		} else {
+			search(mid+1, hi, axis^1)
+			if split*split < bestDist2 {
+				search(lo, mid, axis^1)
+			}
+		}
+	}
+	search(0, len(t.pts), 0)
+
+	if best == -1 {
+		return -1, 0
+	}
+	return t.index[best], math.Sqrt(bestDist2)
+}
+
+// Radius returns the indices (in the slice given to NewKDTree) of all the points
+// within the weighted distance radius of p, in no particular order.
+func (t *KDTree) Radius(p Vec2d, weight Vec2d, radius float64) (indices []int) {
+	q, ok := t.transformQuery(p)
+	if !ok {
+		return nil
+	}
+
+	r2 := radius * radius
+	var search func(lo, hi, axis int)
+	search = func(lo, hi, axis int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		pt := t.pts[mid]
+		dx, dy := (pt[0]-q[0])*weight[0], (pt[1]-q[1])*weight[1]
+		if dx*dx+dy*dy <= r2 {
+			indices = append(indices, t.index[mid])
+		}
+
+		split := (q[axis] - pt[axis]) * weight[axis]
+		if split <= 0 || split*split <= r2 {
+			search(lo, mid, axis^1)
+		}
+		if split >= 0 || split*split <= r2 {
+			search(mid+1, hi, axis^1)
+		}
+	}
+	search(0, len(t.pts), 0)
+	return
+}
+
+// PlotMapping maps between plot coordinates and pixels in the plot area on the screen.
+type PlotMapping struct {
+	Limits         Rectd // Axis limits, with the X range in Left/Width and the Y range in Top/Height
+	Area           Rectd // Plot area in pixels, from the top-left of the screen
+	XScale, YScale AxisScale
+}
+
+// transformedLimits returns the limits after the axis transforms.
+func (m PlotMapping) transformedLimits() (min, max Vec2d) {
+	min[0], _ = m.XScale.transform(m.Limits.Left)
+	max[0], _ = m.XScale.transform(m.Limits.Left + m.Limits.Width)
+	min[1], _ = m.YScale.transform(m.Limits.Top)
+	max[1], _ = m.YScale.transform(m.Limits.Top + m.Limits.Height)
+	return
+}
+
+// Weight returns the pixels per unit on each axis, after the log transform of log axes,
+// to be used as the weight of KDTree queries so distances are in pixels.
+func (m PlotMapping) Weight() Vec2d {
+	min, max := m.transformedLimits()
+	return Vec2d{
+		m.Area.Width / (max[0] - min[0]),
+		m.Area.Height / (max[1] - min[1]),
+	}
+}
+
+// ToPixels maps a point in plot coordinates to pixels.
+func (m PlotMapping) ToPixels(p Vec2d) Vec2d {
+	min, max := m.transformedLimits()
+	x, _ := m.XScale.transform(p[0])
+	y, _ := m.YScale.transform(p[1])
+	return Vec2d{
+		m.Area.Left + (x-min[0])/(max[0]-min[0])*m.Area.Width,
+		m.Area.Top + (max[1]-y)/(max[1]-min[1])*m.Area.Height, // Y goes up in plots and down on the screen
+	}
+}
+
+// FromPixels maps a point in pixels to plot coordinates.
+func (m PlotMapping) FromPixels(px Vec2d) Vec2d {
+	min, max := m.transformedLimits()
+	return Vec2d{
+		m.XScale.inverse(min[0] + (px[0]-m.Area.Left)/m.Area.Width*(max[0]-min[0])),
+		m.YScale.inverse(max[1] - (px[1]-m.Area.Top)/m.Area.Height*(max[1]-min[1])),
+	}
+}
@@ -0,0 +1,154 @@
+package itype
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// bruteDist returns the weighted distance between p and q on the scales, or ok=false
+// if either cannot be shown on them.
+func bruteDist(p, q Vec2d, weight Vec2d, xScale, yScale AxisScale) (dist float64, ok bool) {
+	px, ok1 := xScale.transform(p[0])
+	py, ok2 := yScale.transform(p[1])
+	qx, ok3 := xScale.transform(q[0])
+	qy, ok4 := yScale.transform(q[1])
+	if !(ok1 && ok2 && ok3 && ok4) {
+		return 0, false
+	}
+	return math.Hypot((px-qx)*weight[0], (py-qy)*weight[1]), true
+}
+
+func TestKDTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := make([]Vec2d, 2000)
+	for i := range random {
+		random[i] = Vec2d{rnd.Float64()*2 - 0.2, rnd.Float64()*100 - 10}
+	}
+	random[10] = random[20] // Duplicates
+	random[30] = Vec2d{math.NaN(), 1}
+
+	// Quantized data, with only a few distinct values on each axis
+	quantized := make([]Vec2d, 20000)
+	for i := range quantized {
+		quantized[i] = Vec2d{float64(rnd.Intn(3)+1) * 0.5, float64(rnd.Intn(4))*20 + 5}
+	}
+	same := make([]Vec2d, 5000)
+	for i := range same {
+		same[i] = Vec2d{0.5, 40}
+	}
+
+	sets := []struct {
+		name   string
+		points []Vec2d
+	}{
+		{"random", random},
+		{"quantized", quantized},
+		{"same", same},
+	}
+
+	scales := []struct {
+		name           string
+		xScale, yScale AxisScale
+		weight         Vec2d
+	}{
+		{"linear", ScaleLinear, ScaleLinear, Vec2d{500, 4}},
+		{"log", ScaleLog10, ScaleLog10, Vec2d{300, 200}},
+		{"mixed", ScaleLinear, ScaleLog10, Vec2d{500, 200}},
+	}
+	for _, set := range sets {
+		points := set.points
+		for _, sc := range scales {
+			name := set.name + " " + sc.name
+			tree := NewKDTree(points, sc.xScale, sc.yScale)
+
+			valid := 0
+			for _, p := range points {
+				if _, ok := bruteDist(p, p, sc.weight, sc.xScale, sc.yScale); ok && !math.IsNaN(p[0]) {
+					valid++
+				}
+			}
+			if tree.Len() != valid {
+				t.Errorf("%s: Len() = %d, want %d", name, tree.Len(), valid)
+			}
+
+			for q := 0; q < 200; q++ {
+				query := Vec2d{rnd.Float64()*2 - 0.2, rnd.Float64()*100 - 10}
+				maxDist := rnd.Float64() * 30
+
+				// Brute force
+				bestDist, inRadius := math.Inf(1), []int(nil)
+				for i, p := range points {
+					d, ok := bruteDist(p, query, sc.weight, sc.xScale, sc.yScale)
+					if !ok || math.IsNaN(d) {
+						continue
+					}
+					if d < maxDist && d < bestDist {
+						bestDist = d
+					}
+					if d <= maxDist {
+						inRadius = append(inRadius, i)
+					}
+				}
+				_, queryOk := bruteDist(query, query, sc.weight, sc.xScale, sc.yScale)
+
+				index, dist := tree.Nearest(query, sc.weight, maxDist)
+				switch {
+				case !queryOk || math.IsInf(bestDist, 1):
+					if index != -1 {
+						t.Errorf("%s: Nearest(%v, %v) = %d, want -1", name, query, maxDist, index)
+					}
+				case index == -1:
+					t.Errorf("%s: Nearest(%v, %v) = -1, want distance %v", name, query, maxDist, bestDist)
+				default:
+					d, _ := bruteDist(points[index], query, sc.weight, sc.xScale, sc.yScale)
+					if !approxEqual(dist, bestDist) || !approxEqual(d, bestDist) {
+						t.Errorf("%s: Nearest(%v, %v) = %d at %v, want distance %v", name, query, maxDist, index, dist, bestDist)
+					}
+				}
+
+				got := tree.Radius(query, sc.weight, maxDist)
+				sort.Ints(got)
+				if !queryOk {
+					inRadius = nil
+				}
+				if !approxEqual(got, inRadius) && !(len(got) == 0 && len(inRadius) == 0) {
+					t.Errorf("%s: Radius(%v, %v) = %v, want %v", name, query, maxDist, got, inRadius)
+				}
+			}
+		}
+	}
+
+	empty := NewKDTree(nil, ScaleLinear, ScaleLinear)
+	if i, _ := empty.Nearest(Vec2d{}, Vec2d{1, 1}, 10); i != -1 || empty.Len() != 0 {
+		t.Errorf("empty tree: Nearest() = %d, Len() = %d", i, empty.Len())
+	}
+}
+
+func TestPlotMapping(t *testing.T) {
+	area := Rectd{Left: 100, Top: 50, Width: 400, Height: 200}
+
+	linear := PlotMapping{Limits: Rectd{Left: -1, Top: 0, Width: 2, Height: 10}, Area: area}
+	logs := PlotMapping{Limits: Rectd{Left: 1, Top: 0.01, Width: 999, Height: 0.99}, Area: area, XScale: ScaleLog10, YScale: ScaleLog10}
+
+	runOpCases(t, []opCase{
+		{"linear top-left", linear.ToPixels(Vec2d{-1, 10}), Vec2d{100, 50}},
+		{"linear bottom-right", linear.ToPixels(Vec2d{1, 0}), Vec2d{500, 250}},
+		{"linear center", linear.ToPixels(Vec2d{0, 5}), Vec2d{300, 150}},
+		{"linear FromPixels", linear.FromPixels(Vec2d{200, 100}), Vec2d{-0.5, 7.5}},
+		{"linear Weight", linear.Weight(), Vec2d{200, 20}},
+		{"log bottom-left", logs.ToPixels(Vec2d{1, 0.01}), Vec2d{100, 250}},
+		{"log decades", logs.ToPixels(Vec2d{10, 0.1}), Vec2d{100 + 400.0/3, 150}},
+		{"log FromPixels", logs.FromPixels(Vec2d{100 + 800.0/3, 50}), Vec2d{100, 1}},
+		{"log Weight", logs.Weight(), Vec2d{400.0 / 3, 100}},
+	})
+
+	for _, m := range []PlotMapping{linear, logs} {
+		for _, p := range []Vec2d{{1.5, 0.5}, {3, 0.02}, {700, 0.9}} {
+			if got := m.FromPixels(m.ToPixels(p)); !approxEqual(got, p) {
+				t.Errorf("%v: FromPixels(ToPixels(%v)) = %v", m.Limits, p, got)
+			}
+		}
+	}
+}
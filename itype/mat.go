@@ -0,0 +1,311 @@
+package itype
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mat3 is a 3x3 float64 matrix, in column-major order like OpenGL and mgl32:
+// element (row, col) is at [col*3+row].
+type Mat3 [9]float64
+
+// Mat4 is a 4x4 float64 matrix, in column-major order like OpenGL and mgl32:
+// element (row, col) is at [col*4+row].
+type Mat4 [16]float64
+
+// Ident3 returns the 3x3 identity matrix.
+func Ident3() Mat3 {
+	return Mat3{1, 0, 0, 0, 1, 0, 0, 0, 1}
+}
+
+// Ident4 returns the 4x4 identity matrix.
+func Ident4() Mat4 {
+	return Mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+}
+
+// At returns the element at (row, col).
+func (m Mat3) At(row, col int) float64 { return m[col*3+row] }
+
+// At returns the element at (row, col).
+func (m Mat4) At(row, col int) float64 { return m[col*4+row] }
+
+// Scale3 returns a matrix scaling by s.
+func Scale3(s Vec3d) Mat3 {
+	return Mat3{s[0], 0, 0, 0, s[1], 0, 0, 0, s[2]}
+}
+
+// Rotate3 returns a matrix rotating by angle radians around the axis,
+// counter-clockwise when looking at the origin from the axis.
+func Rotate3(angle float64, axis Vec3d) Mat3 {
+	a := axis.Normalize()
+	x, y, z := a[0], a[1], a[2]
+	s, c := math.Sincos(angle)
+	k := 1 - c
+	return Mat3{
+		x*x*k + c, y*x*k + z*s, x*z*k - y*s,
+		x*y*k - z*s, y*y*k + c, y*z*k + x*s,
+		x*z*k + y*s, y*z*k - x*s, z*z*k + c,
+	}
+}
+
+// Mul returns m*m2.
+func (m Mat3) Mul(m2 Mat3) (r Mat3) {
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			var sum float64
+			for k := 0; k < 3; k++ {
+				sum += m[k*3+row] * m2[col*3+k]
+			}
+			r[col*3+row] = sum
+		}
+	}
+	return
+}
+
+// MulVec3 returns m*v.
+func (m Mat3) MulVec3(v Vec3d) Vec3d {
+	return Vec3d{
+		m[0]*v[0] + m[3]*v[1] + m[6]*v[2],
+		m[1]*v[0] + m[4]*v[1] + m[7]*v[2],
+		m[2]*v[0] + m[5]*v[1] + m[8]*v[2],
+	}
+}
+
+// Transpose returns the transpose of m.
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{m[0], m[3], m[6], m[1], m[4], m[7], m[2], m[5], m[8]}
+}
+
+// Det returns the determinant of m.
+func (m Mat3) Det() float64 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) - m[3]*(m[1]*m[8]-m[7]*m[2]) + m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Inverse returns the inverse of m, or false if m is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Det()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	inv := Mat3{
+		m[4]*m[8] - m[5]*m[7], m[2]*m[7] - m[1]*m[8], m[1]*m[5] - m[2]*m[4],
+		m[5]*m[6] - m[3]*m[8], m[0]*m[8] - m[2]*m[6], m[2]*m[3] - m[0]*m[5],
+		m[3]*m[7] - m[4]*m[6], m[1]*m[6] - m[0]*m[7], m[0]*m[4] - m[1]*m[3],
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
+
+// Mat4 returns m as the upper-left of a 4x4 matrix.
+func (m Mat3) Mat4() Mat4 {
+	return Mat4{
+		m[0], m[1], m[2], 0,
+		m[3], m[4], m[5], 0,
+		m[6], m[7], m[8], 0,
+		0, 0, 0, 1,
+	}
+}
+
+// ToMgl32 converts m to a float32 mgl32.Mat3.
+func (m Mat3) ToMgl32() (r mgl32.Mat3) {
+	for i, v := range m {
+		r[i] = float32(v)
+	}
+	return
+}
+
+// Translate4 returns a matrix translating by t.
+func Translate4(t Vec3d) Mat4 {
+	return Mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, t[0], t[1], t[2], 1}
+}
+
+// Scale4 returns a matrix scaling by s.
+func Scale4(s Vec3d) Mat4 {
+	return Mat4{s[0], 0, 0, 0, 0, s[1], 0, 0, 0, 0, s[2], 0, 0, 0, 0, 1}
+}
+
+// Rotate4 returns a matrix rotating by angle radians around the axis, like Rotate3.
+func Rotate4(angle float64, axis Vec3d) Mat4 {
+	return Rotate3(angle, axis).Mat4()
+}
+
+// LookAt returns a view matrix of a camera at eye looking at center,
+// with up pointing upwards on the screen, like gluLookAt.
+func LookAt(eye, center, up Vec3d) Mat4 {
+	f := center.Sub(eye).Normalize()
+	s := f.Cross(up.Normalize()).Normalize()
+	u := s.Cross(f)
+
+	return Mat4{
+		s[0], u[0], -f[0], 0,
+		s[1], u[1], -f[1], 0,
+		s[2], u[2], -f[2], 0,
+		-s.Dot(eye), -u.Dot(eye), f.Dot(eye), 1,
+	}
+}
+
+// Perspective returns a perspective projection matrix, like gluPerspective,
+// with fovy the vertical field of view in radians.
+func Perspective(fovy, aspect, near, far float64) Mat4 {
+	f := 1 / math.Tan(fovy/2)
+	nf := near - far
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) / nf, -1,
+		0, 0, 2 * far * near / nf, 0,
+	}
+}
+
+// Ortho returns an orthographic projection matrix, like glOrtho.
+func Ortho(left, right, bottom, top, near, far float64) Mat4 {
+	rl, tb, fn := right-left, top-bottom, far-near
+	return Mat4{
+		2 / rl, 0, 0, 0,
+		0, 2 / tb, 0, 0,
+		0, 0, -2 / fn, 0,
+		-(right + left) / rl, -(top + bottom) / tb, -(far + near) / fn, 1,
+	}
+}
+
+// Mul returns m*m2, which transforms by m2 first and then m.
+func (m Mat4) Mul(m2 Mat4) (r Mat4) {
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float64
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * m2[col*4+k]
+			}
+			r[col*4+row] = sum
+		}
+	}
+	return
+}
+
+// MulVec4 returns m*v.
+func (m Mat4) MulVec4(v Vec4d) Vec4d {
+	return Vec4d{
+		m[0]*v[0] + m[4]*v[1] + m[8]*v[2] + m[12]*v[3],
+		m[1]*v[0] + m[5]*v[1] + m[9]*v[2] + m[13]*v[3],
+		m[2]*v[0] + m[6]*v[1] + m[10]*v[2] + m[14]*v[3],
+		m[3]*v[0] + m[7]*v[1] + m[11]*v[2] + m[15]*v[3],
+	}
+}
+
+// Transform transforms the point p, dividing by w for projections.
+func (m Mat4) Transform(p Vec3d) Vec3d {
+	r := m.MulVec4(Vec4d{p[0], p[1], p[2], 1})
+	if r[3] != 0 && r[3] != 1 {
+		return Vec3d{r[0] / r[3], r[1] / r[3], r[2] / r[3]}
+	}
+	return Vec3d{r[0], r[1], r[2]}
+}
+
+// TransformDir transforms the direction d, ignoring the translation.
+func (m Mat4) TransformDir(d Vec3d) Vec3d {
+	return m.Mat3().MulVec3(d)
+}
+
+// Transpose returns the transpose of m.
+func (m Mat4) Transpose() Mat4 {
+	return Mat4{
+		m[0], m[4], m[8], m[12],
+		m[1], m[5], m[9], m[13],
+		m[2], m[6], m[10], m[14],
+		m[3], m[7], m[11], m[15],
+	}
+}
+
+// cofactors returns the 2x2 sub-determinants used by Det and Inverse.
+func (m Mat4) cofactors() (s [6]float64, c [6]float64) {
+	s[0] = m[0]*m[5] - m[4]*m[1]
+	s[1] = m[0]*m[9] - m[8]*m[1]
+	s[2] = m[0]*m[13] - m[12]*m[1]
+	s[3] = m[4]*m[9] - m[8]*m[5]
+	s[4] = m[4]*m[13] - m[12]*m[5]
+	s[5] = m[8]*m[13] - m[12]*m[9]
+
+	c[5] = m[10]*m[15] - m[14]*m[11]
+	c[4] = m[6]*m[15] - m[14]*m[7]
+	c[3] = m[6]*m[11] - m[10]*m[7]
+	c[2] = m[2]*m[15] - m[14]*m[3]
+	c[1] = m[2]*m[11] - m[10]*m[3]
+	c[0] = m[2]*m[7] - m[6]*m[3]
+	return
+}
+
+// Det returns the determinant of m.
+func (m Mat4) Det() float64 {
+	s, c := m.cofactors()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+// Inverse returns the inverse of m, or false if m is singular.
+func (m Mat4) Inverse() (Mat4, bool) {
+	s, c := m.cofactors()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return Mat4{}, false
+	}
+	inv := 1 / det
+
+	// Rows of the input are (m[0], m[4], m[8], m[12]) and so on, column-major
+	a := [4][4]float64{
+		{m[0], m[4], m[8], m[12]},
+		{m[1], m[5], m[9], m[13]},
+		{m[2], m[6], m[10], m[14]},
+		{m[3], m[7], m[11], m[15]},
+	}
+	var r [4][4]float64
+	r[0][0] = (a[1][1]*c[5] - a[1][2]*c[4] + a[1][3]*c[3]) * inv
+	r[0][1] = (-a[0][1]*c[5] + a[0][2]*c[4] - a[0][3]*c[3]) * inv
+	r[0][2] = (a[3][1]*s[5] - a[3][2]*s[4] + a[3][3]*s[3]) * inv
+	r[0][3] = (-a[2][1]*s[5] + a[2][2]*s[4] - a[2][3]*s[3]) * inv
+
+	r[1][0] = (-a[1][0]*c[5] + a[1][2]*c[2] - a[1][3]*c[1]) * inv
+	r[1][1] = (a[0][0]*c[5] - a[0][2]*c[2] + a[0][3]*c[1]) * inv
+	r[1][2] = (-a[3][0]*s[5] + a[3][2]*s[2] - a[3][3]*s[1]) * inv
+	r[1][3] = (a[2][0]*s[5] - a[2][2]*s[2] + a[2][3]*s[1]) * inv
+
+	r[2][0] = (a[1][0]*c[4] - a[1][1]*c[2] + a[1][3]*c[0]) * inv
+	r[2][1] = (-a[0][0]*c[4] + a[0][1]*c[2] - a[0][3]*c[0]) * inv
+	r[2][2] = (a[3][0]*s[4] - a[3][1]*s[2] + a[3][3]*s[0]) * inv
+	r[2][3] = (-a[2][0]*s[4] + a[2][1]*s[2] - a[2][3]*s[0]) * inv
+
+	r[3][0] = (-a[1][0]*c[3] + a[1][1]*c[1] - a[1][2]*c[0]) * inv
+	r[3][1] = (a[0][0]*c[3] - a[0][1]*c[1] + a[0][2]*c[0]) * inv
+	r[3][2] = (-a[3][0]*s[3] + a[3][1]*s[1] - a[3][2]*s[0]) * inv
+	r[3][3] = (a[2][0]*s[3] - a[2][1]*s[1] + a[2][2]*s[0]) * inv
+
+	var out Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			out[col*4+row] = r[row][col]
+		}
+	}
+	return out, true
+}
+
+// Mat3 returns the upper-left 3x3 part of m.
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{m[0], m[1], m[2], m[4], m[5], m[6], m[8], m[9], m[10]}
+}
+
+// ToMgl32 converts m to a float32 mgl32.Mat4, as taken by render.Shader.SetUniformMat4.
+func (m Mat4) ToMgl32() (r mgl32.Mat4) {
+	for i, v := range m {
+		r[i] = float32(v)
+	}
+	return
+}
+
+// Mat4FromMgl32 converts a float32 mgl32.Mat4 to a Mat4.
+func Mat4FromMgl32(m mgl32.Mat4) (r Mat4) {
+	for i, v := range m {
+		r[i] = float64(v)
+	}
+	return
+}
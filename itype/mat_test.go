@@ -0,0 +1,132 @@
+package itype
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestMat4Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mat4
+	}{
+		{"identity", Ident4()},
+		{"translate", Translate4(Vec3d{1, -2, 3})},
+		{"scale", Scale4(Vec3d{2, 0.5, -4})},
+		{"rotate", Rotate4(0.7, Vec3d{1, 2, 3})},
+		{"composite", Translate4(Vec3d{5, 0, -1}).Mul(Rotate4(-1.2, Vec3d{0, 1, 0})).Mul(Scale4(Vec3d{3, 3, 3}))},
+		{"perspective", Perspective(math.Pi/3, 16.0/9, 0.1, 100)},
+		{"view", LookAt(Vec3d{3, 4, 5}, Vec3d{}, Vec3d{0, 1, 0})},
+	}
+	for _, tt := range tests {
+		inv, ok := tt.m.Inverse()
+		if !ok {
+			t.Errorf("%s: Inverse() not ok", tt.name)
+			continue
+		}
+		if got := tt.m.Mul(inv); !approxEqual(got, Ident4()) {
+			t.Errorf("%s: m * m.Inverse() = %v, want identity", tt.name, got)
+		}
+		if got := inv.Mul(tt.m); !approxEqual(got, Ident4()) {
+			t.Errorf("%s: m.Inverse() * m = %v, want identity", tt.name, got)
+		}
+	}
+
+	if _, ok := Scale4(Vec3d{1, 0, 1}).Inverse(); ok {
+		t.Error("Inverse() of a singular matrix is ok")
+	}
+}
+
+func TestMat3Inverse(t *testing.T) {
+	m := Rotate3(1.1, Vec3d{0, 0, 1}).Mul(Scale3(Vec3d{2, 3, 4}))
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("Inverse() not ok")
+	}
+	if got := m.Mul(inv); !approxEqual(got, Ident3()) {
+		t.Errorf("m * m.Inverse() = %v, want identity", got)
+	}
+	if _, ok := (Mat3{}).Inverse(); ok {
+		t.Error("Inverse() of the zero matrix is ok")
+	}
+}
+
+func TestMatDet(t *testing.T) {
+	runOpCases(t, []opCase{
+		{"Ident3.Det", Ident3().Det(), 1.0},
+		{"Scale3.Det", Scale3(Vec3d{2, 3, 4}).Det(), 24.0},
+		{"Rotate3.Det", Rotate3(0.3, Vec3d{1, 1, 0}).Det(), 1.0},
+		{"Scale4.Det", Scale4(Vec3d{2, -3, 4}).Det(), -24.0},
+		{"Translate4.Det", Translate4(Vec3d{7, 8, 9}).Det(), 1.0},
+	})
+}
+
+func TestMatTransform(t *testing.T) {
+	runOpCases(t, []opCase{
+		{"Rotate3 Z", Rotate3(math.Pi/2, Vec3d{0, 0, 1}).MulVec3(Vec3d{1, 0, 0}), Vec3d{0, 1, 0}},
+		{"Rotate3 X", Rotate3(math.Pi/2, Vec3d{1, 0, 0}).MulVec3(Vec3d{0, 1, 0}), Vec3d{0, 0, 1}},
+		{"Translate4", Translate4(Vec3d{1, 2, 3}).Transform(Vec3d{1, 1, 1}), Vec3d{2, 3, 4}},
+		{"TransformDir ignores translation", Translate4(Vec3d{1, 2, 3}).TransformDir(Vec3d{1, 1, 1}), Vec3d{1, 1, 1}},
+		{"Mul order", Translate4(Vec3d{1, 0, 0}).Mul(Scale4(Vec3d{2, 2, 2})).Transform(Vec3d{1, 1, 1}), Vec3d{3, 2, 2}},
+		{"Transpose", Translate4(Vec3d{1, 2, 3}).Transpose().At(3, 0), 1.0},
+		{"At", Translate4(Vec3d{1, 2, 3}).At(1, 3), 2.0},
+		{"Mat4.Mat3", Rotate4(0.5, Vec3d{0, 1, 0}).Mat3(), Rotate3(0.5, Vec3d{0, 1, 0})},
+		{"LookAt eye", LookAt(Vec3d{3, 4, 5}, Vec3d{}, Vec3d{0, 1, 0}).Transform(Vec3d{3, 4, 5}), Vec3d{}},
+		{"LookAt center", LookAt(Vec3d{0, 0, 5}, Vec3d{}, Vec3d{0, 1, 0}).Transform(Vec3d{}), Vec3d{0, 0, -5}},
+	})
+}
+
+func TestProjections(t *testing.T) {
+	persp := Perspective(math.Pi/2, 2, 1, 10)
+	ortho := Ortho(-4, 4, -2, 2, 1, 11)
+	runOpCases(t, []opCase{
+		{"Perspective near", persp.Transform(Vec3d{0, 0, -1}), Vec3d{0, 0, -1}},
+		{"Perspective far", persp.Transform(Vec3d{0, 0, -10}), Vec3d{0, 0, 1}},
+		{"Perspective corner", persp.Transform(Vec3d{2, 1, -1}), Vec3d{1, 1, -1}},
+		{"Ortho min", ortho.Transform(Vec3d{-4, -2, -1}), Vec3d{-1, -1, -1}},
+		{"Ortho max", ortho.Transform(Vec3d{4, 2, -11}), Vec3d{1, 1, 1}},
+	})
+
+	// Same as mathgl, within float32 precision
+	check := func(name string, got Mat4, want mgl32.Mat4) {
+		for i := range got {
+			if math.Abs(got[i]-float64(want[i])) > 1e-5 {
+				t.Errorf("%s = %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+	check("Perspective", Perspective(1, 1.5, 0.1, 100), mgl32.Perspective(1, 1.5, 0.1, 100))
+	check("Ortho", Ortho(-1, 2, -3, 4, 0.5, 20), mgl32.Ortho(-1, 2, -3, 4, 0.5, 20))
+	check("LookAt",
+		LookAt(Vec3d{1, 2, 3}, Vec3d{-1, 0, 2}, Vec3d{0, 1, 0}),
+		mgl32.LookAtV(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{-1, 0, 2}, mgl32.Vec3{0, 1, 0}))
+	check("ToMgl32 round trip", Mat4FromMgl32(persp.ToMgl32()), persp.ToMgl32())
+}
+
+func TestQuaternion(t *testing.T) {
+	axis := Vec3d{1, -2, 0.5}
+	q := QuatRotate(0.9, axis)
+	v := Vec3d{0.3, 4, -1}
+
+	runOpCases(t, []opCase{
+		{"Length", q.Length(), 1.0},
+		{"Rotate", q.Rotate(v), Rotate3(0.9, axis).MulVec3(v)},
+		{"Mat3", q.Mat3(), Rotate3(0.9, axis)},
+		{"Mat4", q.Mat4(), Rotate4(0.9, axis)},
+		{"Mul", QuatRotate(0.4, axis).Mul(QuatRotate(0.5, axis)), q},
+		{"Mul order", QuatRotate(math.Pi/2, Vec3d{0, 0, 1}).Mul(QuatRotate(math.Pi/2, Vec3d{1, 0, 0})).Rotate(Vec3d{0, 0, 1}), Vec3d{1, 0, 0}},
+		{"Inverse", q.Mul(q.Inverse()), QuatIdent()},
+		{"Inverse non-unit", q.Scale(3).Mul(q.Scale(3).Inverse()), QuatIdent()},
+		{"Inverse zero", Quaternion{}.Inverse(), QuatIdent()},
+		{"Normalize", q.Scale(5).Normalize(), q},
+		{"Normalize zero", Quaternion{}.Normalize(), QuatIdent()},
+		{"Slerp 0", QuatIdent().Slerp(q, 0), QuatIdent()},
+		{"Slerp 1", QuatIdent().Slerp(q, 1), q},
+		{"Slerp half", QuatIdent().Slerp(q, 0.5), QuatRotate(0.45, axis)},
+		{"Slerp shortest", QuatIdent().Slerp(q.Scale(-1), 0.5).Rotate(v), QuatRotate(0.45, axis).Rotate(v)},
+		{"Slerp same", q.Slerp(q, 0.3), q},
+	})
+}
@@ -0,0 +1,115 @@
+package itype
+
+import (
+	"math"
+)
+
+// Quaternion is a float64 quaternion W + V, used for rotations.
+type Quaternion struct {
+	W float64
+	V Vec3d
+}
+
+// QuatIdent returns the identity quaternion, which does not rotate.
+func QuatIdent() Quaternion {
+	return Quaternion{W: 1}
+}
+
+// QuatRotate returns the quaternion rotating by angle radians around the axis, like Rotate3.
+func QuatRotate(angle float64, axis Vec3d) Quaternion {
+	s, c := math.Sincos(angle / 2)
+	return Quaternion{W: c, V: axis.Normalize().Multiply(s)}
+}
+
+// Mul returns q*q2, which rotates by q2 first and then q.
+func (q Quaternion) Mul(q2 Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*q2.W - q.V.Dot(q2.V),
+		V: q.V.Cross(q2.V).Add(q2.V.Multiply(q.W)).Add(q.V.Multiply(q2.W)),
+	}
+}
+
+// Scale returns q with every component multiplied by s.
+func (q Quaternion) Scale(s float64) Quaternion {
+	return Quaternion{W: q.W * s, V: q.V.Multiply(s)}
+}
+
+// Add returns the component-wise sum of q and q2.
+func (q Quaternion) Add(q2 Quaternion) Quaternion {
+	return Quaternion{W: q.W + q2.W, V: q.V.Add(q2.V)}
+}
+
+// Dot returns the 4D dot product of q and q2.
+func (q Quaternion) Dot(q2 Quaternion) float64 {
+	return q.W*q2.W + q.V.Dot(q2.V)
+}
+
+// Length returns the norm of q.
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Normalize returns q scaled to unit length, or the identity if q is zero.
+func (q Quaternion) Normalize() Quaternion {
+	l := q.Length()
+	if l == 0 {
+		return QuatIdent()
+	}
+	return q.Scale(1 / l)
+}
+
+// Conjugate returns the conjugate of q, which is its inverse for unit quaternions.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, V: q.V.Negative()}
+}
+
+// Inverse returns the inverse of q, or the identity if q is zero.
+func (q Quaternion) Inverse() Quaternion {
+	d := q.Dot(q)
+	if d == 0 {
+		return QuatIdent()
+	}
+	return q.Conjugate().Scale(1 / d)
+}
+
+// Rotate rotates v by the unit quaternion q.
+func (q Quaternion) Rotate(v Vec3d) Vec3d {
+	// v + 2w(q x v) + 2 q x (q x v)
+	t := q.V.Cross(v).Multiply(2)
+	return v.Add(t.Multiply(q.W)).Add(q.V.Cross(t))
+}
+
+// Mat3 returns the rotation matrix of the unit quaternion q.
+func (q Quaternion) Mat3() Mat3 {
+	w, x, y, z := q.W, q.V[0], q.V[1], q.V[2]
+	return Mat3{
+		1 - 2*(y*y+z*z), 2 * (x*y + w*z), 2 * (x*z - w*y),
+		2 * (x*y - w*z), 1 - 2*(x*x+z*z), 2 * (y*z + w*x),
+		2 * (x*z + w*y), 2 * (y*z - w*x), 1 - 2*(x*x+y*y),
+	}
+}
+
+// Mat4 returns the rotation matrix of the unit quaternion q.
+func (q Quaternion) Mat4() Mat4 {
+	return q.Mat3().Mat4()
+}
+
+// Slerp interpolates spherically from the unit quaternion q (t=0) to q2 (t=1),
+// along the shortest arc and at constant angular speed.
+func (q Quaternion) Slerp(q2 Quaternion, t float64) Quaternion {
+	cos := q.Dot(q2)
+	if cos < 0 {
+		// Take the shorter way around
+		q2 = q2.Scale(-1)
+		cos = -cos
+	}
+
+	// Nearly the same rotation: interpolate linearly, avoiding the division by sin
+	if cos > 1-1e-9 {
+		return q.Scale(1 - t).Add(q2.Scale(t)).Normalize()
+	}
+
+	theta := math.Acos(cos)
+	sin := math.Sin(theta)
+	return q.Scale(math.Sin((1-t)*theta) / sin).Add(q2.Scale(math.Sin(t*theta) / sin))
+}
@@ -0,0 +1,209 @@
+package itype
+
+import (
+	"math"
+)
+
+// Rayd is a float64 ray starting at Origin, going along Dir.
+// Distances along the ray are in lengths of Dir.
+type Rayd struct {
+	Origin, Dir Vec3d
+}
+
+// Rayf is a float32 ray starting at Origin, going along Dir.
+type Rayf struct {
+	Origin, Dir Vec3f
+}
+
+func (r Rayf) ToFloat64() Rayd {
+	return Rayd{Origin: r.Origin.ToFloat64(), Dir: r.Dir.ToFloat64()}
+}
+
+func (r Rayd) ToFloat32() Rayf {
+	return Rayf{Origin: r.Origin.ToFloat32(), Dir: r.Dir.ToFloat32()}
+}
+
+// At returns the point at distance t along the ray.
+func (r Rayd) At(t float64) Vec3d {
+	return r.Origin.Add(r.Dir.Multiply(t))
+}
+
+// At returns the point at distance t along the ray.
+func (r Rayf) At(t float32) Vec3f {
+	return r.Origin.Add(r.Dir.Multiply(t))
+}
+
+// RaydFromScreen returns the ray going from the near plane to the far plane through
+// a point on the screen, for mouse picking.
+//
+// pos is in pixels from the top-left of a viewport of the given size (like imgui mouse
+// positions), and viewProj is the projection matrix times the view matrix.
+// ok is false if viewProj is not invertible.
+func RaydFromScreen(pos, viewport Vec2d, viewProj Mat4) (ray Rayd, ok bool) {
+	inv, ok := viewProj.Inverse()
+	if !ok {
+		return Rayd{}, false
+	}
+	x := pos[0]/viewport[0]*2 - 1
+	y := 1 - pos[1]/viewport[1]*2
+	near := inv.Transform(Vec3d{x, y, -1})
+	far := inv.Transform(Vec3d{x, y, 1})
+	return Rayd{Origin: near, Dir: far.Sub(near)}, true
+}
+
+// slab clips the ray range [tmin, tmax] to the slab [min, max] on one axis,
+// updating the entry face. It returns false if the ray misses the slab.
+//
+// A ray parallel to the slab is in it if origin is in [min, max], or in (min, max)
+// if exclusive is set, so that a ray running along a face does not hit it.
+func slab(origin, dir, min, max float64, axis int, exclusive bool, tmin, tmax *float64, face *Direction) bool {
+	if dir == 0 {
+		// Parallel to the slab: in it all the time, or never
+		if exclusive {
+			return origin > min && origin < max
+		}
+		return origin >= min && origin <= max
+	}
+
+	t1, t2 := (min-origin)/dir, (max-origin)/dir
+	enter := Direction(axis*2 + 1) // The face at min, pointing towards minus
+	if t1 > t2 {
+		t1, t2 = t2, t1
+		enter = Direction(axis * 2) // The face at max, pointing towards plus
+	}
+	if t1 > *tmin {
+		*tmin = t1
+		*face = enter
+	}
+	if t2 < *tmax {
+		*tmax = t2
+	}
+	return *tmin <= *tmax
+}
+
+// intersect is IntersectBox, with tmin allowed to be negative.
+// exclusive is passed on to slab.
+func (r Rayd) intersect(b Boxd, exclusive bool) (hit bool, tmin, tmax float64, face Direction) {
+	tmin, tmax = math.Inf(-1), math.Inf(1)
+	min, max := b.MinPoint(), b.MaxPoint()
+	for axis := 0; axis < 3; axis++ {
+		if !slab(r.Origin[axis], r.Dir[axis], min[axis], max[axis], axis, exclusive, &tmin, &tmax, &face) {
+			return false, 0, 0, 0
+		}
+	}
+	return true, tmin, tmax, face
+}
+
+// IntersectBox intersects the ray with the box using the slab test.
+//
+// dist is the distance along the ray where it enters the box, and face the face of
+// the box it enters by (as its outward normal). If the ray starts inside the box,
+// dist is 0 and face is the face it leaves by.
+func (r Rayd) IntersectBox(b Boxd) (hit bool, dist float64, face Direction) {
+	hit, tmin, tmax, face := r.intersect(b, false)
+	if !hit || tmax < 0 {
+		return false, 0, 0
+	}
+	if tmin < 0 {
+		// Started inside; find the exit face by going backwards
+		_, _, _, face = Rayd{Origin: r.At(tmax), Dir: r.Dir.Negative()}.intersect(b, false)
+		return true, 0, face
+	}
+	return true, tmin, face
+}
+
+// IntersectBox is like Rayd.IntersectBox.
+func (r Rayf) IntersectBox(b Boxf) (hit bool, dist float32, face Direction) {
+	hit, d, face := r.ToFloat64().IntersectBox(b.ToFloat64())
+	return hit, float32(d), face
+}
+
+// Pick returns the index of the first box hit by the ray, with the distance and face
+// like IntersectBox. index is -1 if no box is hit.
+func (r Rayd) Pick(boxes []Boxd) (index int, dist float64, face Direction) {
+	index = -1
+	for i, b := range boxes {
+		if hit, d, f := r.IntersectBox(b); hit && (index == -1 || d < dist) {
+			index, dist, face = i, d, f
+		}
+	}
+	return
+}
+
+// Pick is like Rayd.Pick.
+func (r Rayf) Pick(boxes []Boxf) (index int, dist float32, face Direction) {
+	index = -1
+	r64 := r.ToFloat64()
+	for i, b := range boxes {
+		if hit, d, f := r64.IntersectBox(b.ToFloat64()); hit && (index == -1 || float32(d) < dist) {
+			index, dist, face = i, float32(d), f
+		}
+	}
+	return
+}
+
+func (b Boxf) ToFloat64() Boxd {
+	return Boxd{
+		OffX:  float64(b.OffX),
+		OffY:  float64(b.OffY),
+		OffZ:  float64(b.OffZ),
+		SizeX: float64(b.SizeX),
+		SizeY: float64(b.SizeY),
+		SizeZ: float64(b.SizeZ),
+	}
+}
+
+// sweepEpsilon is how far a box may already be past a face and still collide with it,
+// for boxes resting exactly against another.
+const sweepEpsilon = 1e-9
+
+// Sweep moves the box by move, stopping at the boxes in the way and sliding along them.
+//
+// It returns the moved box, and the faces of the obstacles it hit (as their outward normals;
+// YPlus means the box landed on top of something). Obstacles the box already overlaps are ignored.
+func (b Boxd) Sweep(move Vec3d, obstacles []Boxd) (moved Boxd, hits []Direction) {
+	moved = b
+
+	// Each collision blocks movement along one axis, so three rounds are enough
+	for round := 0; round < 3 && move != (Vec3d{}); round++ {
+		ray := Rayd{Origin: moved.MinPoint(), Dir: move}
+		first, firstFace := 1.0, Direction(-1)
+
+		for _, o := range obstacles {
+			// Grow the obstacle by the box, so the box can be swept as a point
+			grown := Boxd{
+				OffX: o.OffX - moved.SizeX, SizeX: o.SizeX + moved.SizeX,
+				OffY: o.OffY - moved.SizeY, SizeY: o.SizeY + moved.SizeY,
+				OffZ: o.OffZ - moved.SizeZ, SizeZ: o.SizeZ + moved.SizeZ,
+			}
+			// Only a box strictly overlapping the obstacle on the axes it does not move along can
+			// hit it, so one resting on a row of boxes slides over the seams between them
+			hit, tmin, tmax, face := ray.intersect(grown, true)
+			if !hit || tmin >= tmax || tmin < -sweepEpsilon || tmin > first {
+				continue
+			}
+			first, firstFace = math.Max(tmin, 0), face
+		}
+
+		moved = moved.Offset(move.Multiply(first))
+		if firstFace == -1 {
+			break
+		}
+		hits = append(hits, firstFace)
+
+		// Slide along the face with the rest of the movement
+		move = move.Multiply(1 - first)
+		move[firstFace/2] = 0
+	}
+	return
+}
+
+// Sweep is like Boxd.Sweep.
+func (b Boxf) Sweep(move Vec3f, obstacles []Boxf) (moved Boxf, hits []Direction) {
+	obs := make([]Boxd, len(obstacles))
+	for i, o := range obstacles {
+		obs[i] = o.ToFloat64()
+	}
+	m, hits := b.ToFloat64().Sweep(move.ToFloat64(), obs)
+	return m.ToFloat32(), hits
+}
@@ -0,0 +1,116 @@
+package itype
+
+import (
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	tiles := []Boxd{
+		{0, 0, 0, 1, 1, 1},
+		{1, 0, 0, 1, 1, 1},
+	}
+
+	tests := []struct {
+		name      string
+		box       Boxd
+		move      Vec3d
+		obstacles []Boxd
+		want      Boxd
+		hits      []Direction
+	}{
+		{
+			"free",
+			Boxd{0, 5, 0, 1, 1, 1}, Vec3d{1, 2, 3}, tiles,
+			Boxd{1, 7, 3, 1, 1, 1}, nil,
+		},
+		{
+			"over a seam",
+			Boxd{0.2, 1, 0.2, 0.5, 1.5, 0.5}, Vec3d{1.5, 0, 0}, tiles,
+			Boxd{1.7, 1, 0.2, 0.5, 1.5, 0.5}, nil,
+		},
+		{
+			"flush against a side",
+			Boxd{-1, 1, 0, 1, 1, 1}, Vec3d{0, -1, 0}, tiles,
+			Boxd{-1, 0, 0, 1, 1, 1}, nil,
+		},
+		{
+			"wall",
+			Boxd{-3, 0, 0, 1, 1, 1}, Vec3d{3, 0, 0}, tiles,
+			Boxd{-1, 0, 0, 1, 1, 1}, []Direction{XMinus},
+		},
+		{
+			"slide along a wall",
+			Boxd{-3, 0, 0, 1, 1, 1}, Vec3d{4, 0, 0.4}, tiles,
+			Boxd{-1, 0, 0.4, 1, 1, 1}, []Direction{XMinus},
+		},
+		{
+			"land and slide",
+			Boxd{0.2, 3, 0.2, 0.5, 1, 0.5}, Vec3d{1, -5, 0}, tiles,
+			Boxd{1.2, 1, 0.2, 0.5, 1, 0.5}, []Direction{YPlus},
+		},
+		{
+			"into a corner",
+			Boxd{0.2, 3, -3, 0.5, 1, 0.5}, Vec3d{0, -4, 4}, []Boxd{{-5, 0, -5, 10, 1, 10}, {-5, 0, 0, 10, 5, 1}},
+			Boxd{0.2, 1, -0.5, 0.5, 1, 0.5}, []Direction{YPlus, ZMinus},
+		},
+		{
+			"already overlapping",
+			Boxd{0.5, 0.5, 0.5, 1, 1, 1}, Vec3d{0, 0, 2}, tiles,
+			Boxd{0.5, 0.5, 2.5, 1, 1, 1}, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		moved, hits := tt.box.Sweep(tt.move, tt.obstacles)
+		if !approxEqual(moved, tt.want) || !approxEqual(hits, tt.hits) {
+			t.Errorf("%s: Sweep() = %v, %v, want %v, %v", tt.name, moved, hits, tt.want, tt.hits)
+		}
+	}
+
+	// Boxf goes through Boxd
+	moved, hits := Boxf{0.2, 1, 0.2, 0.5, 1.5, 0.5}.Sweep(Vec3f{1.5, 0, 0}, []Boxf{{0, 0, 0, 1, 1, 1}, {1, 0, 0, 1, 1, 1}})
+	if !approxEqual(moved, Boxf{1.7, 1, 0.2, 0.5, 1.5, 0.5}) || len(hits) != 0 {
+		t.Errorf("Boxf.Sweep() = %v, %v", moved, hits)
+	}
+}
+
+func TestIntersectBox(t *testing.T) {
+	box := Boxd{0, 0, 0, 1, 1, 1}
+
+	tests := []struct {
+		name string
+		ray  Rayd
+		hit  bool
+		dist float64
+		face Direction
+	}{
+		{"front", Rayd{Vec3d{0.5, 0.5, -2}, Vec3d{0, 0, 1}}, true, 2, ZMinus},
+		{"back", Rayd{Vec3d{0.5, 0.5, 3}, Vec3d{0, 0, -2}}, true, 1, ZPlus},
+		{"diagonal", Rayd{Vec3d{-1, 2, 0.5}, Vec3d{1, -1, 0}}, true, 1, XMinus},
+		{"along an edge", Rayd{Vec3d{0, 1, -5}, Vec3d{0, 0, 1}}, true, 5, ZMinus},
+		{"inside", Rayd{Vec3d{0.5, 0.5, 0.5}, Vec3d{1, 0, 0}}, true, 0, XPlus},
+		{"miss", Rayd{Vec3d{2, 0.5, -2}, Vec3d{0, 0, 1}}, false, 0, 0},
+		{"behind", Rayd{Vec3d{0.5, 0.5, 2}, Vec3d{0, 0, 1}}, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		hit, dist, face := tt.ray.IntersectBox(box)
+		if hit != tt.hit || !approxEqual(dist, tt.dist) || face != tt.face {
+			t.Errorf("%s: IntersectBox() = %v, %v, %v, want %v, %v, %v", tt.name, hit, dist, face, tt.hit, tt.dist, tt.face)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	boxes := []Boxd{
+		{0, 0, 5, 1, 1, 1},
+		{0, 0, 2, 1, 1, 1},
+		{5, 0, 0, 1, 1, 1},
+	}
+	if i, dist, face := (Rayd{Vec3d{0.5, 0.5, 0}, Vec3d{0, 0, 1}}).Pick(boxes); i != 1 || !approxEqual(dist, 2.0) || face != ZMinus {
+		t.Errorf("Pick() = %d, %v, %v, want 1, 2, Z-", i, dist, face)
+	}
+	if i, _, _ := (Rayd{Vec3d{0.5, 0.5, 0}, Vec3d{0, 1, 0}}).Pick(boxes); i != -1 {
+		t.Errorf("Pick() of a miss = %d, want -1", i)
+	}
+}
@@ -0,0 +1,239 @@
+package itype
+
+// Rectangle operations. The rectangles span [Left, Left+Width) horizontally
+// and [Top, Top+Height) vertically; one with no width or height is empty.
+
+// Recti methods
+
+func (r Recti) MinPoint() Vec2i {
+	return Vec2i{r.Left, r.Top}
+}
+
+func (r Recti) MaxPoint() Vec2i {
+	return Vec2i{r.Left + r.Width, r.Top + r.Height}
+}
+
+func (r Recti) Size() Vec2i {
+	return Vec2i{r.Width, r.Height}
+}
+
+// RectiFromPoints returns the rectangle from min to max.
+func RectiFromPoints(min, max Vec2i) Recti {
+	return Recti{Left: min[0], Top: min[1], Width: max[0] - min[0], Height: max[1] - min[1]}
+}
+
+// Empty returns true if the rectangle has no area.
+func (r Recti) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Center returns the center point of the rectangle.
+func (r Recti) Center() Vec2i {
+	return Vec2i{r.Left + r.Width/2, r.Top + r.Height/2}
+}
+
+// Contains returns true if the point is in the rectangle.
+func (r Recti) Contains(point Vec2i) bool {
+	return point[0] >= r.Left && point[0] < r.Left+r.Width &&
+		point[1] >= r.Top && point[1] < r.Top+r.Height
+}
+
+// Overlaps returns true if the two rectangles share some area.
+func (r Recti) Overlaps(r2 Recti) bool {
+	ok, _ := r.Intersect(r2)
+	return ok
+}
+
+// Intersect returns the overlapping part of the two rectangles, if any.
+func (r Recti) Intersect(r2 Recti) (ok bool, intersect Recti) {
+	intersect = RectiFromPoints(r.MinPoint().Max(r2.MinPoint()), r.MaxPoint().Min(r2.MaxPoint()))
+	if intersect.Empty() {
+		return false, Recti{}
+	}
+	return true, intersect
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+// An empty rectangle is ignored.
+func (r Recti) Union(r2 Recti) Recti {
+	switch {
+	case r.Empty():
+		return r2
+	case r2.Empty():
+		return r
+	}
+	return RectiFromPoints(r.MinPoint().Min(r2.MinPoint()), r.MaxPoint().Max(r2.MaxPoint()))
+}
+
+// Inset moves every side of the rectangle inwards by d, which may be negative.
+func (r Recti) Inset(d int) Recti {
+	return Recti{Left: r.Left + d, Top: r.Top + d, Width: r.Width - 2*d, Height: r.Height - 2*d}
+}
+
+// Expand moves every side of the rectangle outwards by d, which may be negative.
+func (r Recti) Expand(d int) Recti {
+	return r.Inset(-d)
+}
+
+// Translate moves the rectangle by offset.
+func (r Recti) Translate(offset Vec2i) Recti {
+	return Recti{Left: r.Left + offset[0], Top: r.Top + offset[1], Width: r.Width, Height: r.Height}
+}
+
+// Rectf methods
+
+// RectfFromPoints returns the rectangle from min to max.
+func RectfFromPoints(min, max Vec2f) Rectf {
+	return Rectf{Left: min[0], Top: min[1], Width: max[0] - min[0], Height: max[1] - min[1]}
+}
+
+// Empty returns true if the rectangle has no area.
+func (r Rectf) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Center returns the center point of the rectangle.
+func (r Rectf) Center() Vec2f {
+	return Vec2f{r.Left + r.Width/2, r.Top + r.Height/2}
+}
+
+// Contains returns true if the point is in the rectangle.
+func (r Rectf) Contains(point Vec2f) bool {
+	return point[0] >= r.Left && point[0] < r.Left+r.Width &&
+		point[1] >= r.Top && point[1] < r.Top+r.Height
+}
+
+// Overlaps returns true if the two rectangles share some area.
+func (r Rectf) Overlaps(r2 Rectf) bool {
+	ok, _ := r.Intersect(r2)
+	return ok
+}
+
+// Intersect returns the overlapping part of the two rectangles, if any.
+func (r Rectf) Intersect(r2 Rectf) (ok bool, intersect Rectf) {
+	intersect = RectfFromPoints(r.MinPoint().Max(r2.MinPoint()), r.MaxPoint().Min(r2.MaxPoint()))
+	if intersect.Empty() {
+		return false, Rectf{}
+	}
+	return true, intersect
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+// An empty rectangle is ignored.
+func (r Rectf) Union(r2 Rectf) Rectf {
+	switch {
+	case r.Empty():
+		return r2
+	case r2.Empty():
+		return r
+	}
+	return RectfFromPoints(r.MinPoint().Min(r2.MinPoint()), r.MaxPoint().Max(r2.MaxPoint()))
+}
+
+// Inset moves every side of the rectangle inwards by d, which may be negative.
+func (r Rectf) Inset(d float32) Rectf {
+	return Rectf{Left: r.Left + d, Top: r.Top + d, Width: r.Width - 2*d, Height: r.Height - 2*d}
+}
+
+// Expand moves every side of the rectangle outwards by d, which may be negative.
+func (r Rectf) Expand(d float32) Rectf {
+	return r.Inset(-d)
+}
+
+// Translate moves the rectangle by offset.
+func (r Rectf) Translate(offset Vec2f) Rectf {
+	return Rectf{Left: r.Left + offset[0], Top: r.Top + offset[1], Width: r.Width, Height: r.Height}
+}
+
+// Scissor converts a rectangle in framebuffer pixels from the top-left, like an imgui
+// clip rectangle, into an OpenGL scissor box (from the bottom-left) for a framebuffer
+// fbHeight pixels high. The coordinates are truncated to integers.
+func (r Rectf) Scissor(fbHeight int) Recti {
+	return Recti{
+		Left:   int(r.Left),
+		Top:    fbHeight - int(r.Top+r.Height),
+		Width:  int(r.Width),
+		Height: int(r.Height),
+	}
+}
+
+// Rectd methods
+
+// RectdFromPoints returns the rectangle from min to max.
+func RectdFromPoints(min, max Vec2d) Rectd {
+	return Rectd{Left: min[0], Top: min[1], Width: max[0] - min[0], Height: max[1] - min[1]}
+}
+
+// Empty returns true if the rectangle has no area.
+func (r Rectd) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Center returns the center point of the rectangle.
+func (r Rectd) Center() Vec2d {
+	return Vec2d{r.Left + r.Width/2, r.Top + r.Height/2}
+}
+
+// Contains returns true if the point is in the rectangle.
+func (r Rectd) Contains(point Vec2d) bool {
+	return point[0] >= r.Left && point[0] < r.Left+r.Width &&
+		point[1] >= r.Top && point[1] < r.Top+r.Height
+}
+
+// Overlaps returns true if the two rectangles share some area.
+func (r Rectd) Overlaps(r2 Rectd) bool {
+	ok, _ := r.Intersect(r2)
+	return ok
+}
+
+// Intersect returns the overlapping part of the two rectangles, if any.
+func (r Rectd) Intersect(r2 Rectd) (ok bool, intersect Rectd) {
+	intersect = RectdFromPoints(r.MinPoint().Max(r2.MinPoint()), r.MaxPoint().Min(r2.MaxPoint()))
+	if intersect.Empty() {
+		return false, Rectd{}
+	}
+	return true, intersect
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+// An empty rectangle is ignored.
+func (r Rectd) Union(r2 Rectd) Rectd {
+	switch {
+	case r.Empty():
+		return r2
+	case r2.Empty():
+		return r
+	}
+	return RectdFromPoints(r.MinPoint().Min(r2.MinPoint()), r.MaxPoint().Max(r2.MaxPoint()))
+}
+
+// Inset moves every side of the rectangle inwards by d, which may be negative.
+func (r Rectd) Inset(d float64) Rectd {
+	return Rectd{Left: r.Left + d, Top: r.Top + d, Width: r.Width - 2*d, Height: r.Height - 2*d}
+}
+
+// Expand moves every side of the rectangle outwards by d, which may be negative.
+func (r Rectd) Expand(d float64) Rectd {
+	return r.Inset(-d)
+}
+
+// Translate moves the rectangle by offset.
+func (r Rectd) Translate(offset Vec2d) Rectd {
+	return Rectd{Left: r.Left + offset[0], Top: r.Top + offset[1], Width: r.Width, Height: r.Height}
+}
+
+func (r Recti) ToFloat32() Rectf {
+	return Rectf{Left: float32(r.Left), Top: float32(r.Top), Width: float32(r.Width), Height: float32(r.Height)}
+}
+
+func (r Recti) ToFloat64() Rectd {
+	return Rectd{Left: float64(r.Left), Top: float64(r.Top), Width: float64(r.Width), Height: float64(r.Height)}
+}
+
+func (r Rectf) ToFloat64() Rectd {
+	return Rectd{Left: float64(r.Left), Top: float64(r.Top), Width: float64(r.Width), Height: float64(r.Height)}
+}
+
+func (r Rectd) ToFloat32() Rectf {
+	return Rectf{Left: float32(r.Left), Top: float32(r.Top), Width: float32(r.Width), Height: float32(r.Height)}
+}
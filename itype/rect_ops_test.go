@@ -0,0 +1,64 @@
+package itype
+
+import (
+	"testing"
+)
+
+func TestRectOps(t *testing.T) {
+	intersecti := func(a, b Recti) Recti {
+		_, r := a.Intersect(b)
+		return r
+	}
+	intersectd := func(a, b Rectd) Rectd {
+		_, r := a.Intersect(b)
+		return r
+	}
+
+	r := Recti{Left: 10, Top: 20, Width: 30, Height: 40}
+	rd := Rectd{Left: 1, Top: 2, Width: 3, Height: 4}
+
+	runOpCases(t, []opCase{
+		// Contains: the left and top edges are inside, the right and bottom ones are not
+		{"Recti.Contains(top-left)", r.Contains(Vec2i{10, 20}), true},
+		{"Recti.Contains(inside)", r.Contains(Vec2i{39, 59}), true},
+		{"Recti.Contains(right edge)", r.Contains(Vec2i{40, 30}), false},
+		{"Recti.Contains(bottom edge)", r.Contains(Vec2i{15, 60}), false},
+		{"Recti.Contains(left of)", r.Contains(Vec2i{9, 30}), false},
+		{"Recti.Contains(empty)", Recti{Left: 10, Top: 20}.Contains(Vec2i{10, 20}), false},
+		{"Rectd.Contains(top-left)", rd.Contains(Vec2d{1, 2}), true},
+		{"Rectd.Contains(bottom-right)", rd.Contains(Vec2d{4, 6}), false},
+		{"Rectd.Contains(just inside)", rd.Contains(Vec2d{3.999, 5.999}), true},
+		{"Rectf.Contains(right edge)", Rectf{Width: 1, Height: 1}.Contains(Vec2f{1, 0.5}), false},
+
+		// Intersect: rectangles sharing only an edge or a corner do not overlap
+		{"Recti.Intersect", intersecti(r, Recti{Left: 30, Top: 0, Width: 20, Height: 30}), Recti{Left: 30, Top: 20, Width: 10, Height: 10}},
+		{"Recti.Intersect(inside)", intersecti(r, Recti{Left: 15, Top: 25, Width: 5, Height: 5}), Recti{Left: 15, Top: 25, Width: 5, Height: 5}},
+		{"Recti.Intersect(one pixel)", intersecti(r, Recti{Left: 39, Top: 59, Width: 5, Height: 5}), Recti{Left: 39, Top: 59, Width: 1, Height: 1}},
+		{"Recti.Intersect(right edge)", intersecti(r, Recti{Left: 40, Top: 20, Width: 5, Height: 5}), Recti{}},
+		{"Recti.Intersect(corner)", intersecti(r, Recti{Left: 40, Top: 60, Width: 5, Height: 5}), Recti{}},
+		{"Recti.Intersect(apart)", intersecti(r, Recti{Left: 100, Top: 100, Width: 5, Height: 5}), Recti{}},
+		{"Recti.Intersect(empty)", intersecti(r, Recti{Left: 15, Top: 25}), Recti{}},
+		{"Recti.Overlaps", r.Overlaps(Recti{Left: 39, Top: 0, Width: 5, Height: 21}), true},
+		{"Recti.Overlaps(bottom edge)", r.Overlaps(Recti{Left: 0, Top: 60, Width: 100, Height: 5}), false},
+		{"Rectd.Intersect", intersectd(rd, Rectd{Left: 3.5, Top: 0, Width: 1, Height: 2.5}), Rectd{Left: 3.5, Top: 2, Width: 0.5, Height: 0.5}},
+		{"Rectd.Intersect(edge)", intersectd(rd, Rectd{Left: 4, Top: 2, Width: 1, Height: 1}), Rectd{}},
+
+		// Union: empty rectangles are ignored, even if they are far away
+		{"Recti.Union", r.Union(Recti{Left: 0, Top: 50, Width: 5, Height: 30}), Recti{Left: 0, Top: 20, Width: 40, Height: 60}},
+		{"Recti.Union(inside)", r.Union(Recti{Left: 15, Top: 25, Width: 5, Height: 5}), r},
+		{"Recti.Union(empty)", r.Union(Recti{Left: -100, Top: -100}), r},
+		{"Recti.Union(no height)", r.Union(Recti{Left: 100, Top: 100, Width: 5}), r},
+		{"Recti.Union(negative size)", r.Union(Recti{Left: 100, Top: 100, Width: -5, Height: 5}), r},
+		{"Recti.Union(of empty)", Recti{Left: -100, Top: -100}.Union(r), r},
+		{"Recti.Union(both empty)", Recti{Left: 1, Width: 5}.Union(Recti{Top: 1, Height: 5}), Recti{Top: 1, Height: 5}},
+		{"Rectd.Union", rd.Union(Rectd{Left: 0.5, Top: 5, Width: 1, Height: 2}), Rectd{Left: 0.5, Top: 2, Width: 3.5, Height: 5}},
+		{"Rectd.Union(empty)", rd.Union(Rectd{Left: 50, Top: 50, Height: 1}), rd},
+
+		// Scissor: from the top-left to the bottom-left of the framebuffer
+		{"Rectf.Scissor", Rectf{Left: 10, Top: 20, Width: 30, Height: 40}.Scissor(100), Recti{Left: 10, Top: 40, Width: 30, Height: 40}},
+		{"Rectf.Scissor(top)", Rectf{Left: 0, Top: 0, Width: 50, Height: 10}.Scissor(100), Recti{Left: 0, Top: 90, Width: 50, Height: 10}},
+		{"Rectf.Scissor(bottom)", Rectf{Left: 0, Top: 90, Width: 50, Height: 10}.Scissor(100), Recti{Left: 0, Top: 0, Width: 50, Height: 10}},
+		{"Rectf.Scissor(whole)", Rectf{Width: 640, Height: 480}.Scissor(480), Recti{Width: 640, Height: 480}},
+		{"Rectf.Scissor(fraction)", Rectf{Left: 1.5, Top: 2.5, Width: 3.75, Height: 4.75}.Scissor(10), Recti{Left: 1, Top: 3, Width: 3, Height: 4}},
+	})
+}
@@ -77,6 +77,9 @@ func (v Vec2f) Length() float32 {
 
 func (v Vec2f) Normalize() Vec2f {
 	l := v.Length()
+	if l == 0 {
+		return v
+	}
 	return Vec2f{v[0] / l, v[1] / l}
 }
 
@@ -117,6 +120,9 @@ func (v Vec3f) Length() float32 {
 
 func (v Vec3f) Normalize() Vec3f {
 	l := v.Length()
+	if l == 0 {
+		return v
+	}
 	return Vec3f{v[0] / l, v[1] / l, v[2] / l}
 }
 
@@ -154,6 +160,9 @@ func (v Vec2d) Length() float64 {
 
 func (v Vec2d) Normalize() Vec2d {
 	l := v.Length()
+	if l == 0 {
+		return v
+	}
 	return Vec2d{v[0] / l, v[1] / l}
 }
 
@@ -198,6 +207,9 @@ func (v Vec3d) Length() float64 {
 
 func (v Vec3d) Normalize() Vec3d {
 	l := v.Length()
+	if l == 0 {
+		return v
+	}
 	return Vec3d{v[0] / l, v[1] / l, v[2] / l}
 }
 
@@ -0,0 +1,606 @@
+package itype
+
+import (
+	"math"
+)
+
+// Component-wise arithmetic, products, and conversions for all the vector types.
+//
+// Mul and Div are component-wise; Multiply (MultiplyInt for int vectors) scales by a number.
+// Lengths and distances of int vectors are float64.
+// Normalize returns the zero vector unchanged instead of dividing by zero.
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs32(a float32) float32 {
+	return float32(math.Abs(float64(a)))
+}
+
+func min64(a, b float64) float64 { return math.Min(a, b) }
+func max64(a, b float64) float64 { return math.Max(a, b) }
+func abs64(a float64) float64    { return math.Abs(a) }
+
+// Vec2i methods
+
+func (v Vec2i) Sub(sub Vec2i) Vec2i {
+	return Vec2i{v[0] - sub[0], v[1] - sub[1]}
+}
+
+func (v Vec2i) Mul(mult Vec2i) Vec2i {
+	return Vec2i{v[0] * mult[0], v[1] * mult[1]}
+}
+
+func (v Vec2i) Div(div Vec2i) Vec2i {
+	return Vec2i{v[0] / div[0], v[1] / div[1]}
+}
+
+func (v Vec2i) Negative() Vec2i {
+	return Vec2i{-v[0], -v[1]}
+}
+
+func (v Vec2i) Dot(v2 Vec2i) int {
+	return v[0]*v2[0] + v[1]*v2[1]
+}
+
+// Cross returns the z component of the cross product of the two vectors extended to 3D.
+func (v Vec2i) Cross(v2 Vec2i) int {
+	return v[0]*v2[1] - v[1]*v2[0]
+}
+
+func (v Vec2i) Length() float64 {
+	return math.Sqrt(float64(v.Dot(v)))
+}
+
+func (v Vec2i) Distance(v2 Vec2i) float64 {
+	return v.Sub(v2).Length()
+}
+
+func (v Vec2i) Min(v2 Vec2i) Vec2i {
+	return Vec2i{minInt(v[0], v2[0]), minInt(v[1], v2[1])}
+}
+
+func (v Vec2i) Max(v2 Vec2i) Vec2i {
+	return Vec2i{maxInt(v[0], v2[0]), maxInt(v[1], v2[1])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec2i) Clamp(min, max Vec2i) Vec2i {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec2i) Abs() Vec2i {
+	return Vec2i{absInt(v[0]), absInt(v[1])}
+}
+
+func (v Vec2i) ToFloat64() Vec2d {
+	return Vec2d{float64(v[0]), float64(v[1])}
+}
+
+// Vec3i methods
+
+func (v Vec3i) Sub(sub Vec3i) Vec3i {
+	return Vec3i{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2]}
+}
+
+func (v Vec3i) Mul(mult Vec3i) Vec3i {
+	return Vec3i{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2]}
+}
+
+func (v Vec3i) Div(div Vec3i) Vec3i {
+	return Vec3i{v[0] / div[0], v[1] / div[1], v[2] / div[2]}
+}
+
+func (v Vec3i) Negative() Vec3i {
+	return Vec3i{-v[0], -v[1], -v[2]}
+}
+
+func (v Vec3i) Dot(v2 Vec3i) int {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2]
+}
+
+func (v Vec3i) Cross(v2 Vec3i) Vec3i {
+	return Vec3i{v[1]*v2[2] - v[2]*v2[1], v[2]*v2[0] - v[0]*v2[2], v[0]*v2[1] - v[1]*v2[0]}
+}
+
+func (v Vec3i) Length() float64 {
+	return math.Sqrt(float64(v.Dot(v)))
+}
+
+func (v Vec3i) Distance(v2 Vec3i) float64 {
+	return v.Sub(v2).Length()
+}
+
+func (v Vec3i) Min(v2 Vec3i) Vec3i {
+	return Vec3i{minInt(v[0], v2[0]), minInt(v[1], v2[1]), minInt(v[2], v2[2])}
+}
+
+func (v Vec3i) Max(v2 Vec3i) Vec3i {
+	return Vec3i{maxInt(v[0], v2[0]), maxInt(v[1], v2[1]), maxInt(v[2], v2[2])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec3i) Clamp(min, max Vec3i) Vec3i {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec3i) Abs() Vec3i {
+	return Vec3i{absInt(v[0]), absInt(v[1]), absInt(v[2])}
+}
+
+// Vec4i methods
+
+func (v Vec4i) Sub(sub Vec4i) Vec4i {
+	return Vec4i{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2], v[3] - sub[3]}
+}
+
+func (v Vec4i) Mul(mult Vec4i) Vec4i {
+	return Vec4i{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2], v[3] * mult[3]}
+}
+
+func (v Vec4i) Div(div Vec4i) Vec4i {
+	return Vec4i{v[0] / div[0], v[1] / div[1], v[2] / div[2], v[3] / div[3]}
+}
+
+func (v Vec4i) Negative() Vec4i {
+	return Vec4i{-v[0], -v[1], -v[2], -v[3]}
+}
+
+func (v Vec4i) Dot(v2 Vec4i) int {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2] + v[3]*v2[3]
+}
+
+func (v Vec4i) Length() float64 {
+	return math.Sqrt(float64(v.Dot(v)))
+}
+
+func (v Vec4i) Distance(v2 Vec4i) float64 {
+	return v.Sub(v2).Length()
+}
+
+func (v Vec4i) Min(v2 Vec4i) Vec4i {
+	return Vec4i{minInt(v[0], v2[0]), minInt(v[1], v2[1]), minInt(v[2], v2[2]), minInt(v[3], v2[3])}
+}
+
+func (v Vec4i) Max(v2 Vec4i) Vec4i {
+	return Vec4i{maxInt(v[0], v2[0]), maxInt(v[1], v2[1]), maxInt(v[2], v2[2]), maxInt(v[3], v2[3])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec4i) Clamp(min, max Vec4i) Vec4i {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec4i) Abs() Vec4i {
+	return Vec4i{absInt(v[0]), absInt(v[1]), absInt(v[2]), absInt(v[3])}
+}
+
+func (v Vec4i) ToFloat32() Vec4f {
+	return Vec4f{float32(v[0]), float32(v[1]), float32(v[2]), float32(v[3])}
+}
+
+func (v Vec4i) ToFloat64() Vec4d {
+	return Vec4d{float64(v[0]), float64(v[1]), float64(v[2]), float64(v[3])}
+}
+
+// Vec2f methods
+
+func (v Vec2f) Sub(sub Vec2f) Vec2f {
+	return Vec2f{v[0] - sub[0], v[1] - sub[1]}
+}
+
+func (v Vec2f) Mul(mult Vec2f) Vec2f {
+	return Vec2f{v[0] * mult[0], v[1] * mult[1]}
+}
+
+func (v Vec2f) Div(div Vec2f) Vec2f {
+	return Vec2f{v[0] / div[0], v[1] / div[1]}
+}
+
+func (v Vec2f) Negative() Vec2f {
+	return Vec2f{-v[0], -v[1]}
+}
+
+func (v Vec2f) Dot(v2 Vec2f) float32 {
+	return v[0]*v2[0] + v[1]*v2[1]
+}
+
+// Cross returns the z component of the cross product of the two vectors extended to 3D.
+func (v Vec2f) Cross(v2 Vec2f) float32 {
+	return v[0]*v2[1] - v[1]*v2[0]
+}
+
+func (v Vec2f) Distance(v2 Vec2f) float32 {
+	return v.Sub(v2).Length()
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec2f) Lerp(v2 Vec2f, t float32) Vec2f {
+	return Vec2f{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t}
+}
+
+func (v Vec2f) Min(v2 Vec2f) Vec2f {
+	return Vec2f{min32(v[0], v2[0]), min32(v[1], v2[1])}
+}
+
+func (v Vec2f) Max(v2 Vec2f) Vec2f {
+	return Vec2f{max32(v[0], v2[0]), max32(v[1], v2[1])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec2f) Clamp(min, max Vec2f) Vec2f {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec2f) Abs() Vec2f {
+	return Vec2f{abs32(v[0]), abs32(v[1])}
+}
+
+func (v Vec2f) Ceiling() Vec2i {
+	return Vec2i{int(math.Ceil(float64(v[0]))), int(math.Ceil(float64(v[1])))}
+}
+
+func (v Vec2f) Round() Vec2i {
+	return Vec2i{int(math.Round(float64(v[0]))), int(math.Round(float64(v[1])))}
+}
+
+// Vec3f methods
+
+func (v Vec3f) Sub(sub Vec3f) Vec3f {
+	return Vec3f{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2]}
+}
+
+func (v Vec3f) Mul(mult Vec3f) Vec3f {
+	return Vec3f{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2]}
+}
+
+func (v Vec3f) Div(div Vec3f) Vec3f {
+	return Vec3f{v[0] / div[0], v[1] / div[1], v[2] / div[2]}
+}
+
+func (v Vec3f) Dot(v2 Vec3f) float32 {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2]
+}
+
+func (v Vec3f) Cross(v2 Vec3f) Vec3f {
+	return Vec3f{v[1]*v2[2] - v[2]*v2[1], v[2]*v2[0] - v[0]*v2[2], v[0]*v2[1] - v[1]*v2[0]}
+}
+
+func (v Vec3f) Distance(v2 Vec3f) float32 {
+	return v.Sub(v2).Length()
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec3f) Lerp(v2 Vec3f, t float32) Vec3f {
+	return Vec3f{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t, v[2] + (v2[2]-v[2])*t}
+}
+
+func (v Vec3f) Min(v2 Vec3f) Vec3f {
+	return Vec3f{min32(v[0], v2[0]), min32(v[1], v2[1]), min32(v[2], v2[2])}
+}
+
+func (v Vec3f) Max(v2 Vec3f) Vec3f {
+	return Vec3f{max32(v[0], v2[0]), max32(v[1], v2[1]), max32(v[2], v2[2])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec3f) Clamp(min, max Vec3f) Vec3f {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec3f) Abs() Vec3f {
+	return Vec3f{abs32(v[0]), abs32(v[1]), abs32(v[2])}
+}
+
+func (v Vec3f) Ceiling() Vec3i {
+	return Vec3i{int(math.Ceil(float64(v[0]))), int(math.Ceil(float64(v[1]))), int(math.Ceil(float64(v[2])))}
+}
+
+func (v Vec3f) Round() Vec3i {
+	return Vec3i{int(math.Round(float64(v[0]))), int(math.Round(float64(v[1]))), int(math.Round(float64(v[2])))}
+}
+
+// Vec4f methods
+
+func (v Vec4f) Add(add Vec4f) Vec4f {
+	return Vec4f{v[0] + add[0], v[1] + add[1], v[2] + add[2], v[3] + add[3]}
+}
+
+func (v Vec4f) Sub(sub Vec4f) Vec4f {
+	return Vec4f{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2], v[3] - sub[3]}
+}
+
+func (v Vec4f) Mul(mult Vec4f) Vec4f {
+	return Vec4f{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2], v[3] * mult[3]}
+}
+
+func (v Vec4f) Div(div Vec4f) Vec4f {
+	return Vec4f{v[0] / div[0], v[1] / div[1], v[2] / div[2], v[3] / div[3]}
+}
+
+func (v Vec4f) Multiply(mult float32) Vec4f {
+	return Vec4f{v[0] * mult, v[1] * mult, v[2] * mult, v[3] * mult}
+}
+
+func (v Vec4f) Negative() Vec4f {
+	return Vec4f{-v[0], -v[1], -v[2], -v[3]}
+}
+
+func (v Vec4f) Dot(v2 Vec4f) float32 {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2] + v[3]*v2[3]
+}
+
+func (v Vec4f) Length() float32 {
+	return float32(math.Sqrt(float64(v.Dot(v))))
+}
+
+func (v Vec4f) Distance(v2 Vec4f) float32 {
+	return v.Sub(v2).Length()
+}
+
+func (v Vec4f) Normalize() Vec4f {
+	l := v.Length()
+	if l == 0 {
+		return v
+	}
+	return Vec4f{v[0] / l, v[1] / l, v[2] / l, v[3] / l}
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec4f) Lerp(v2 Vec4f, t float32) Vec4f {
+	return Vec4f{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t, v[2] + (v2[2]-v[2])*t, v[3] + (v2[3]-v[3])*t}
+}
+
+func (v Vec4f) Min(v2 Vec4f) Vec4f {
+	return Vec4f{min32(v[0], v2[0]), min32(v[1], v2[1]), min32(v[2], v2[2]), min32(v[3], v2[3])}
+}
+
+func (v Vec4f) Max(v2 Vec4f) Vec4f {
+	return Vec4f{max32(v[0], v2[0]), max32(v[1], v2[1]), max32(v[2], v2[2]), max32(v[3], v2[3])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec4f) Clamp(min, max Vec4f) Vec4f {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec4f) Abs() Vec4f {
+	return Vec4f{abs32(v[0]), abs32(v[1]), abs32(v[2]), abs32(v[3])}
+}
+
+func (v Vec4f) Floor() Vec4i {
+	return Vec4i{int(math.Floor(float64(v[0]))), int(math.Floor(float64(v[1]))), int(math.Floor(float64(v[2]))), int(math.Floor(float64(v[3])))}
+}
+
+func (v Vec4f) Ceiling() Vec4i {
+	return Vec4i{int(math.Ceil(float64(v[0]))), int(math.Ceil(float64(v[1]))), int(math.Ceil(float64(v[2]))), int(math.Ceil(float64(v[3])))}
+}
+
+func (v Vec4f) Round() Vec4i {
+	return Vec4i{int(math.Round(float64(v[0]))), int(math.Round(float64(v[1]))), int(math.Round(float64(v[2]))), int(math.Round(float64(v[3])))}
+}
+
+func (v Vec4f) ToFloat64() Vec4d {
+	return Vec4d{float64(v[0]), float64(v[1]), float64(v[2]), float64(v[3])}
+}
+
+// Vec2d methods
+
+func (v Vec2d) Sub(sub Vec2d) Vec2d {
+	return Vec2d{v[0] - sub[0], v[1] - sub[1]}
+}
+
+func (v Vec2d) Mul(mult Vec2d) Vec2d {
+	return Vec2d{v[0] * mult[0], v[1] * mult[1]}
+}
+
+func (v Vec2d) Div(div Vec2d) Vec2d {
+	return Vec2d{v[0] / div[0], v[1] / div[1]}
+}
+
+func (v Vec2d) Negative() Vec2d {
+	return Vec2d{-v[0], -v[1]}
+}
+
+func (v Vec2d) Dot(v2 Vec2d) float64 {
+	return v[0]*v2[0] + v[1]*v2[1]
+}
+
+// Cross returns the z component of the cross product of the two vectors extended to 3D.
+func (v Vec2d) Cross(v2 Vec2d) float64 {
+	return v[0]*v2[1] - v[1]*v2[0]
+}
+
+func (v Vec2d) Distance(v2 Vec2d) float64 {
+	return v.Sub(v2).Length()
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec2d) Lerp(v2 Vec2d, t float64) Vec2d {
+	return Vec2d{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t}
+}
+
+func (v Vec2d) Min(v2 Vec2d) Vec2d {
+	return Vec2d{min64(v[0], v2[0]), min64(v[1], v2[1])}
+}
+
+func (v Vec2d) Max(v2 Vec2d) Vec2d {
+	return Vec2d{max64(v[0], v2[0]), max64(v[1], v2[1])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec2d) Clamp(min, max Vec2d) Vec2d {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec2d) Abs() Vec2d {
+	return Vec2d{abs64(v[0]), abs64(v[1])}
+}
+
+func (v Vec2d) Ceiling() Vec2i {
+	return Vec2i{int(math.Ceil(v[0])), int(math.Ceil(v[1]))}
+}
+
+func (v Vec2d) Round() Vec2i {
+	return Vec2i{int(math.Round(v[0])), int(math.Round(v[1]))}
+}
+
+// Vec3d methods
+
+func (v Vec3d) Sub(sub Vec3d) Vec3d {
+	return Vec3d{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2]}
+}
+
+func (v Vec3d) Mul(mult Vec3d) Vec3d {
+	return Vec3d{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2]}
+}
+
+func (v Vec3d) Div(div Vec3d) Vec3d {
+	return Vec3d{v[0] / div[0], v[1] / div[1], v[2] / div[2]}
+}
+
+func (v Vec3d) Distance(v2 Vec3d) float64 {
+	return v.Sub(v2).Length()
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec3d) Lerp(v2 Vec3d, t float64) Vec3d {
+	return Vec3d{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t, v[2] + (v2[2]-v[2])*t}
+}
+
+func (v Vec3d) Min(v2 Vec3d) Vec3d {
+	return Vec3d{min64(v[0], v2[0]), min64(v[1], v2[1]), min64(v[2], v2[2])}
+}
+
+func (v Vec3d) Max(v2 Vec3d) Vec3d {
+	return Vec3d{max64(v[0], v2[0]), max64(v[1], v2[1]), max64(v[2], v2[2])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec3d) Clamp(min, max Vec3d) Vec3d {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec3d) Abs() Vec3d {
+	return Vec3d{abs64(v[0]), abs64(v[1]), abs64(v[2])}
+}
+
+func (v Vec3d) Round() Vec3i {
+	return Vec3i{int(math.Round(v[0])), int(math.Round(v[1])), int(math.Round(v[2]))}
+}
+
+// Vec4d methods
+
+func (v Vec4d) Add(add Vec4d) Vec4d {
+	return Vec4d{v[0] + add[0], v[1] + add[1], v[2] + add[2], v[3] + add[3]}
+}
+
+func (v Vec4d) Sub(sub Vec4d) Vec4d {
+	return Vec4d{v[0] - sub[0], v[1] - sub[1], v[2] - sub[2], v[3] - sub[3]}
+}
+
+func (v Vec4d) Mul(mult Vec4d) Vec4d {
+	return Vec4d{v[0] * mult[0], v[1] * mult[1], v[2] * mult[2], v[3] * mult[3]}
+}
+
+func (v Vec4d) Div(div Vec4d) Vec4d {
+	return Vec4d{v[0] / div[0], v[1] / div[1], v[2] / div[2], v[3] / div[3]}
+}
+
+func (v Vec4d) Multiply(mult float64) Vec4d {
+	return Vec4d{v[0] * mult, v[1] * mult, v[2] * mult, v[3] * mult}
+}
+
+func (v Vec4d) Negative() Vec4d {
+	return Vec4d{-v[0], -v[1], -v[2], -v[3]}
+}
+
+func (v Vec4d) Dot(v2 Vec4d) float64 {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2] + v[3]*v2[3]
+}
+
+func (v Vec4d) Length() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+func (v Vec4d) Distance(v2 Vec4d) float64 {
+	return v.Sub(v2).Length()
+}
+
+func (v Vec4d) Normalize() Vec4d {
+	l := v.Length()
+	if l == 0 {
+		return v
+	}
+	return Vec4d{v[0] / l, v[1] / l, v[2] / l, v[3] / l}
+}
+
+// Lerp interpolates linearly from v (t=0) to v2 (t=1).
+func (v Vec4d) Lerp(v2 Vec4d, t float64) Vec4d {
+	return Vec4d{v[0] + (v2[0]-v[0])*t, v[1] + (v2[1]-v[1])*t, v[2] + (v2[2]-v[2])*t, v[3] + (v2[3]-v[3])*t}
+}
+
+func (v Vec4d) Min(v2 Vec4d) Vec4d {
+	return Vec4d{min64(v[0], v2[0]), min64(v[1], v2[1]), min64(v[2], v2[2]), min64(v[3], v2[3])}
+}
+
+func (v Vec4d) Max(v2 Vec4d) Vec4d {
+	return Vec4d{max64(v[0], v2[0]), max64(v[1], v2[1]), max64(v[2], v2[2]), max64(v[3], v2[3])}
+}
+
+// Clamp clamps each component of v into [min, max].
+func (v Vec4d) Clamp(min, max Vec4d) Vec4d {
+	return v.Max(min).Min(max)
+}
+
+func (v Vec4d) Abs() Vec4d {
+	return Vec4d{abs64(v[0]), abs64(v[1]), abs64(v[2]), abs64(v[3])}
+}
+
+func (v Vec4d) Floor() Vec4i {
+	return Vec4i{int(math.Floor(v[0])), int(math.Floor(v[1])), int(math.Floor(v[2])), int(math.Floor(v[3]))}
+}
+
+func (v Vec4d) Ceiling() Vec4i {
+	return Vec4i{int(math.Ceil(v[0])), int(math.Ceil(v[1])), int(math.Ceil(v[2])), int(math.Ceil(v[3]))}
+}
+
+func (v Vec4d) Round() Vec4i {
+	return Vec4i{int(math.Round(v[0])), int(math.Round(v[1])), int(math.Round(v[2])), int(math.Round(v[3]))}
+}
+
+func (v Vec4d) ToFloat32() Vec4f {
+	return Vec4f{float32(v[0]), float32(v[1]), float32(v[2]), float32(v[3])}
+}
@@ -0,0 +1,279 @@
+package itype
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// approxEqual compares numbers, and arrays and structs of them, allowing
+// float values to differ by a relative or absolute error of 1e-6.
+func approxEqual(a, b interface{}) bool {
+	return approxEqualValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func approxEqualValue(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return math.IsNaN(x) && math.IsNaN(y)
+		}
+		return math.Abs(x-y) <= 1e-6*math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+	case reflect.Array, reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !approxEqualValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !approxEqualValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// opCase is the result of one operation, compared with approxEqual.
+type opCase struct {
+	name      string
+	got, want interface{}
+}
+
+func runOpCases(t *testing.T, cases []opCase) {
+	t.Helper()
+	for _, c := range cases {
+		if !approxEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestVecInt(t *testing.T) {
+	runOpCases(t, []opCase{
+		// Vec2i
+		{"Vec2i.Add", Vec2i{1, 2}.Add(Vec2i{3, -4}), Vec2i{4, -2}},
+		{"Vec2i.Sub", Vec2i{1, 2}.Sub(Vec2i{3, -4}), Vec2i{-2, 6}},
+		{"Vec2i.Mul", Vec2i{2, -3}.Mul(Vec2i{4, 5}), Vec2i{8, -15}},
+		{"Vec2i.Div", Vec2i{7, -7}.Div(Vec2i{2, 2}), Vec2i{3, -3}},
+		{"Vec2i.MultiplyInt", Vec2i{2, -3}.MultiplyInt(3), Vec2i{6, -9}},
+		{"Vec2i.Negative", Vec2i{2, -3}.Negative(), Vec2i{-2, 3}},
+		{"Vec2i.Dot", Vec2i{1, 2}.Dot(Vec2i{3, 4}), 11},
+		{"Vec2i.Cross", Vec2i{1, 0}.Cross(Vec2i{0, 1}), 1},
+		{"Vec2i.Length", Vec2i{3, 4}.Length(), 5.0},
+		{"Vec2i.Distance", Vec2i{1, 1}.Distance(Vec2i{4, 5}), 5.0},
+		{"Vec2i.Min", Vec2i{1, 5}.Min(Vec2i{3, 2}), Vec2i{1, 2}},
+		{"Vec2i.Max", Vec2i{1, 5}.Max(Vec2i{3, 2}), Vec2i{3, 5}},
+		{"Vec2i.Clamp", Vec2i{-5, 50}.Clamp(Vec2i{0, 0}, Vec2i{10, 10}), Vec2i{0, 10}},
+		{"Vec2i.Abs", Vec2i{-5, 5}.Abs(), Vec2i{5, 5}},
+		{"Vec2i.ToFloat32", Vec2i{1, -2}.ToFloat32(), Vec2f{1, -2}},
+		{"Vec2i.ToFloat64", Vec2i{1, -2}.ToFloat64(), Vec2d{1, -2}},
+
+		// Vec3i
+		{"Vec3i.Add", Vec3i{1, 2, 3}.Add(Vec3i{3, -4, 5}), Vec3i{4, -2, 8}},
+		{"Vec3i.Sub", Vec3i{1, 2, 3}.Sub(Vec3i{3, -4, 5}), Vec3i{-2, 6, -2}},
+		{"Vec3i.Mul", Vec3i{2, -3, 4}.Mul(Vec3i{4, 5, 6}), Vec3i{8, -15, 24}},
+		{"Vec3i.Div", Vec3i{7, -7, 9}.Div(Vec3i{2, 2, -4}), Vec3i{3, -3, -2}},
+		{"Vec3i.MultiplyInt", Vec3i{2, -3, 4}.MultiplyInt(-2), Vec3i{-4, 6, -8}},
+		{"Vec3i.Negative", Vec3i{2, -3, 0}.Negative(), Vec3i{-2, 3, 0}},
+		{"Vec3i.Dot", Vec3i{1, 2, 3}.Dot(Vec3i{4, 5, 6}), 32},
+		{"Vec3i.Cross", Vec3i{1, 0, 0}.Cross(Vec3i{0, 1, 0}), Vec3i{0, 0, 1}},
+		{"Vec3i.Length", Vec3i{2, 3, 6}.Length(), 7.0},
+		{"Vec3i.Distance", Vec3i{1, 1, 1}.Distance(Vec3i{3, 4, 7}), 7.0},
+		{"Vec3i.Min", Vec3i{1, 5, 3}.Min(Vec3i{3, 2, 3}), Vec3i{1, 2, 3}},
+		{"Vec3i.Max", Vec3i{1, 5, 3}.Max(Vec3i{3, 2, 3}), Vec3i{3, 5, 3}},
+		{"Vec3i.Clamp", Vec3i{-5, 5, 50}.Clamp(Vec3i{0, 0, 0}, Vec3i{10, 10, 10}), Vec3i{0, 5, 10}},
+		{"Vec3i.Abs", Vec3i{-5, 5, 0}.Abs(), Vec3i{5, 5, 0}},
+		{"Vec3i.ToFloat32", Vec3i{1, -2, 3}.ToFloat32(), Vec3f{1, -2, 3}},
+		{"Vec3i.ToFloat64", Vec3i{1, -2, 3}.ToFloat64(), Vec3d{1, -2, 3}},
+
+		// Vec4i
+		{"Vec4i.Add", Vec4i{1, 2, 3, 4}.Add(Vec4i{1, 1, 1, -4}), Vec4i{2, 3, 4, 0}},
+		{"Vec4i.Sub", Vec4i{1, 2, 3, 4}.Sub(Vec4i{1, 1, 1, -4}), Vec4i{0, 1, 2, 8}},
+		{"Vec4i.Mul", Vec4i{1, 2, 3, 4}.Mul(Vec4i{2, 2, -1, 0}), Vec4i{2, 4, -3, 0}},
+		{"Vec4i.Div", Vec4i{9, 8, -7, 1}.Div(Vec4i{2, 3, 2, 2}), Vec4i{4, 2, -3, 0}},
+		{"Vec4i.MultiplyInt", Vec4i{1, 2, 3, 4}.MultiplyInt(2), Vec4i{2, 4, 6, 8}},
+		{"Vec4i.Negative", Vec4i{1, -2, 3, 0}.Negative(), Vec4i{-1, 2, -3, 0}},
+		{"Vec4i.Dot", Vec4i{1, 2, 3, 4}.Dot(Vec4i{4, 3, 2, 1}), 20},
+		{"Vec4i.Length", Vec4i{1, 1, 1, 1}.Length(), 2.0},
+		{"Vec4i.Distance", Vec4i{1, 1, 1, 1}.Distance(Vec4i{2, 2, 2, 2}), 2.0},
+		{"Vec4i.Min", Vec4i{1, 5, 3, -1}.Min(Vec4i{3, 2, 3, 0}), Vec4i{1, 2, 3, -1}},
+		{"Vec4i.Max", Vec4i{1, 5, 3, -1}.Max(Vec4i{3, 2, 3, 0}), Vec4i{3, 5, 3, 0}},
+		{"Vec4i.Clamp", Vec4i{-5, 5, 50, 0}.Clamp(Vec4i{0, 0, 0, 1}, Vec4i{10, 10, 10, 2}), Vec4i{0, 5, 10, 1}},
+		{"Vec4i.Abs", Vec4i{-1, 2, -3, 0}.Abs(), Vec4i{1, 2, 3, 0}},
+		{"Vec4i.ToFloat32", Vec4i{1, -2, 3, 4}.ToFloat32(), Vec4f{1, -2, 3, 4}},
+		{"Vec4i.ToFloat64", Vec4i{1, -2, 3, 4}.ToFloat64(), Vec4d{1, -2, 3, 4}},
+	})
+}
+
+func TestVecFloat32(t *testing.T) {
+	runOpCases(t, []opCase{
+		// Vec2f
+		{"Vec2f.Add", Vec2f{1, 2}.Add(Vec2f{0.5, -4}), Vec2f{1.5, -2}},
+		{"Vec2f.Sub", Vec2f{1, 2}.Sub(Vec2f{0.5, -4}), Vec2f{0.5, 6}},
+		{"Vec2f.Mul", Vec2f{2, -3}.Mul(Vec2f{0.5, 2}), Vec2f{1, -6}},
+		{"Vec2f.Div", Vec2f{1, -3}.Div(Vec2f{2, 2}), Vec2f{0.5, -1.5}},
+		{"Vec2f.Multiply", Vec2f{1, -3}.Multiply(2), Vec2f{2, -6}},
+		{"Vec2f.Negative", Vec2f{1, -3}.Negative(), Vec2f{-1, 3}},
+		{"Vec2f.Dot", Vec2f{1, 2}.Dot(Vec2f{3, 4}), float32(11)},
+		{"Vec2f.Cross", Vec2f{0, 1}.Cross(Vec2f{1, 0}), float32(-1)},
+		{"Vec2f.Length", Vec2f{3, 4}.Length(), float32(5)},
+		{"Vec2f.Distance", Vec2f{1, 1}.Distance(Vec2f{4, 5}), float32(5)},
+		{"Vec2f.Normalize", Vec2f{3, 4}.Normalize(), Vec2f{0.6, 0.8}},
+		{"Vec2f.Normalize(zero)", Vec2f{}.Normalize(), Vec2f{}},
+		{"Vec2f.Lerp", Vec2f{0, 10}.Lerp(Vec2f{10, 20}, 0.25), Vec2f{2.5, 12.5}},
+		{"Vec2f.Min", Vec2f{1, 5}.Min(Vec2f{3, 2}), Vec2f{1, 2}},
+		{"Vec2f.Max", Vec2f{1, 5}.Max(Vec2f{3, 2}), Vec2f{3, 5}},
+		{"Vec2f.Clamp", Vec2f{-1, 0.5}.Clamp(Vec2f{0, 0}, Vec2f{1, 1}), Vec2f{0, 0.5}},
+		{"Vec2f.Abs", Vec2f{-1.5, 2}.Abs(), Vec2f{1.5, 2}},
+		{"Vec2f.Floor", Vec2f{1.5, -1.5}.Floor(), Vec2i{1, -2}},
+		{"Vec2f.Ceiling", Vec2f{1.5, -1.5}.Ceiling(), Vec2i{2, -1}},
+		{"Vec2f.Round", Vec2f{1.4, -1.6}.Round(), Vec2i{1, -2}},
+		{"Vec2f.ToFloat64", Vec2f{1.5, -2}.ToFloat64(), Vec2d{1.5, -2}},
+
+		// Vec3f
+		{"Vec3f.Add", Vec3f{1, 2, 3}.Add(Vec3f{0.5, -4, 1}), Vec3f{1.5, -2, 4}},
+		{"Vec3f.Sub", Vec3f{1, 2, 3}.Sub(Vec3f{0.5, -4, 1}), Vec3f{0.5, 6, 2}},
+		{"Vec3f.Mul", Vec3f{2, -3, 1}.Mul(Vec3f{0.5, 2, 0}), Vec3f{1, -6, 0}},
+		{"Vec3f.Div", Vec3f{1, -3, 4}.Div(Vec3f{2, 2, 8}), Vec3f{0.5, -1.5, 0.5}},
+		{"Vec3f.Multiply", Vec3f{1, -3, 2}.Multiply(0.5), Vec3f{0.5, -1.5, 1}},
+		{"Vec3f.Negative", Vec3f{1, -3, 0}.Negative(), Vec3f{-1, 3, 0}},
+		{"Vec3f.Dot", Vec3f{1, 2, 3}.Dot(Vec3f{4, 5, 6}), float32(32)},
+		{"Vec3f.Cross", Vec3f{0, 1, 0}.Cross(Vec3f{0, 0, 1}), Vec3f{1, 0, 0}},
+		{"Vec3f.Length", Vec3f{2, 3, 6}.Length(), float32(7)},
+		{"Vec3f.Distance", Vec3f{1, 1, 1}.Distance(Vec3f{3, 4, 7}), float32(7)},
+		{"Vec3f.Normalize", Vec3f{0, 0, -2}.Normalize(), Vec3f{0, 0, -1}},
+		{"Vec3f.Normalize(zero)", Vec3f{}.Normalize(), Vec3f{}},
+		{"Vec3f.Lerp", Vec3f{0, 0, 0}.Lerp(Vec3f{4, -8, 2}, 0.5), Vec3f{2, -4, 1}},
+		{"Vec3f.Min", Vec3f{1, 5, 3}.Min(Vec3f{3, 2, 3}), Vec3f{1, 2, 3}},
+		{"Vec3f.Max", Vec3f{1, 5, 3}.Max(Vec3f{3, 2, 3}), Vec3f{3, 5, 3}},
+		{"Vec3f.Clamp", Vec3f{-1, 0.5, 2}.Clamp(Vec3f{0, 0, 0}, Vec3f{1, 1, 1}), Vec3f{0, 0.5, 1}},
+		{"Vec3f.Abs", Vec3f{-1.5, 2, -0}.Abs(), Vec3f{1.5, 2, 0}},
+		{"Vec3f.Floor", Vec3f{1.5, -1.5, 2}.Floor(), Vec3i{1, -2, 2}},
+		{"Vec3f.Ceiling", Vec3f{1.5, -1.5, 2}.Ceiling(), Vec3i{2, -1, 2}},
+		{"Vec3f.Round", Vec3f{1.4, -1.6, 2.5}.Round(), Vec3i{1, -2, 3}},
+		{"Vec3f.ToFloat64", Vec3f{1.5, -2, 3}.ToFloat64(), Vec3d{1.5, -2, 3}},
+
+		// Vec4f
+		{"Vec4f.Add", Vec4f{1, 2, 3, 4}.Add(Vec4f{0.5, 0.5, 0.5, 0.5}), Vec4f{1.5, 2.5, 3.5, 4.5}},
+		{"Vec4f.Sub", Vec4f{1, 2, 3, 4}.Sub(Vec4f{0.5, 0.5, 0.5, 0.5}), Vec4f{0.5, 1.5, 2.5, 3.5}},
+		{"Vec4f.Mul", Vec4f{1, 2, 3, 4}.Mul(Vec4f{2, 0.5, -1, 0}), Vec4f{2, 1, -3, 0}},
+		{"Vec4f.Div", Vec4f{1, 2, 3, 4}.Div(Vec4f{2, 4, -2, 8}), Vec4f{0.5, 0.5, -1.5, 0.5}},
+		{"Vec4f.Multiply", Vec4f{1, 2, 3, 4}.Multiply(-1), Vec4f{-1, -2, -3, -4}},
+		{"Vec4f.Negative", Vec4f{1, -2, 3, 0}.Negative(), Vec4f{-1, 2, -3, 0}},
+		{"Vec4f.Dot", Vec4f{1, 2, 3, 4}.Dot(Vec4f{4, 3, 2, 1}), float32(20)},
+		{"Vec4f.Length", Vec4f{1, 1, 1, 1}.Length(), float32(2)},
+		{"Vec4f.Distance", Vec4f{1, 1, 1, 1}.Distance(Vec4f{2, 2, 2, 2}), float32(2)},
+		{"Vec4f.Normalize", Vec4f{2, 2, 2, 2}.Normalize(), Vec4f{0.5, 0.5, 0.5, 0.5}},
+		{"Vec4f.Normalize(zero)", Vec4f{}.Normalize(), Vec4f{}},
+		{"Vec4f.Lerp", Vec4f{0, 0, 0, 1}.Lerp(Vec4f{1, 2, 3, 1}, 1), Vec4f{1, 2, 3, 1}},
+		{"Vec4f.Min", Vec4f{1, 5, 3, -1}.Min(Vec4f{3, 2, 3, 0}), Vec4f{1, 2, 3, -1}},
+		{"Vec4f.Max", Vec4f{1, 5, 3, -1}.Max(Vec4f{3, 2, 3, 0}), Vec4f{3, 5, 3, 0}},
+		{"Vec4f.Clamp", Vec4f{-1, 0.5, 2, 1}.Clamp(Vec4f{}, Vec4f{1, 1, 1, 1}), Vec4f{0, 0.5, 1, 1}},
+		{"Vec4f.Abs", Vec4f{-1, 2, -3, 0}.Abs(), Vec4f{1, 2, 3, 0}},
+		{"Vec4f.Floor", Vec4f{1.5, -1.5, 2, -0.1}.Floor(), Vec4i{1, -2, 2, -1}},
+		{"Vec4f.Ceiling", Vec4f{1.5, -1.5, 2, -0.1}.Ceiling(), Vec4i{2, -1, 2, 0}},
+		{"Vec4f.Round", Vec4f{1.4, -1.6, 2.5, -2.5}.Round(), Vec4i{1, -2, 3, -3}},
+		{"Vec4f.ToFloat64", Vec4f{1.5, -2, 3, 4}.ToFloat64(), Vec4d{1.5, -2, 3, 4}},
+	})
+}
+
+func TestVecFloat64(t *testing.T) {
+	runOpCases(t, []opCase{
+		// Vec2d
+		{"Vec2d.Add", Vec2d{1, 2}.Add(Vec2d{0.5, -4}), Vec2d{1.5, -2}},
+		{"Vec2d.Sub", Vec2d{1, 2}.Sub(Vec2d{0.5, -4}), Vec2d{0.5, 6}},
+		{"Vec2d.Mul", Vec2d{2, -3}.Mul(Vec2d{0.5, 2}), Vec2d{1, -6}},
+		{"Vec2d.Div", Vec2d{1, -3}.Div(Vec2d{2, 2}), Vec2d{0.5, -1.5}},
+		{"Vec2d.Multiply", Vec2d{1, -3}.Multiply(2), Vec2d{2, -6}},
+		{"Vec2d.Negative", Vec2d{1, -3}.Negative(), Vec2d{-1, 3}},
+		{"Vec2d.Dot", Vec2d{1, 2}.Dot(Vec2d{3, 4}), 11.0},
+		{"Vec2d.Cross", Vec2d{2, 0}.Cross(Vec2d{0, 3}), 6.0},
+		{"Vec2d.Length", Vec2d{3, 4}.Length(), 5.0},
+		{"Vec2d.Distance", Vec2d{1, 1}.Distance(Vec2d{4, 5}), 5.0},
+		{"Vec2d.Normalize", Vec2d{3, 4}.Normalize(), Vec2d{0.6, 0.8}},
+		{"Vec2d.Normalize(zero)", Vec2d{}.Normalize(), Vec2d{}},
+		{"Vec2d.Lerp", Vec2d{0, 10}.Lerp(Vec2d{10, 20}, 0.25), Vec2d{2.5, 12.5}},
+		{"Vec2d.Min", Vec2d{1, 5}.Min(Vec2d{3, 2}), Vec2d{1, 2}},
+		{"Vec2d.Max", Vec2d{1, 5}.Max(Vec2d{3, 2}), Vec2d{3, 5}},
+		{"Vec2d.Clamp", Vec2d{-1, 0.5}.Clamp(Vec2d{0, 0}, Vec2d{1, 1}), Vec2d{0, 0.5}},
+		{"Vec2d.Abs", Vec2d{-1.5, 2}.Abs(), Vec2d{1.5, 2}},
+		{"Vec2d.Floor", Vec2d{1.5, -1.5}.Floor(), Vec2i{1, -2}},
+		{"Vec2d.Ceiling", Vec2d{1.5, -1.5}.Ceiling(), Vec2i{2, -1}},
+		{"Vec2d.Round", Vec2d{1.4, -1.6}.Round(), Vec2i{1, -2}},
+		{"Vec2d.ToFloat32", Vec2d{1.5, -2}.ToFloat32(), Vec2f{1.5, -2}},
+
+		// Vec3d
+		{"Vec3d.Add", Vec3d{1, 2, 3}.Add(Vec3d{0.5, -4, 1}), Vec3d{1.5, -2, 4}},
+		{"Vec3d.Sub", Vec3d{1, 2, 3}.Sub(Vec3d{0.5, -4, 1}), Vec3d{0.5, 6, 2}},
+		{"Vec3d.Mul", Vec3d{2, -3, 1}.Mul(Vec3d{0.5, 2, 0}), Vec3d{1, -6, 0}},
+		{"Vec3d.Div", Vec3d{1, -3, 4}.Div(Vec3d{2, 2, 8}), Vec3d{0.5, -1.5, 0.5}},
+		{"Vec3d.Multiply", Vec3d{1, -3, 2}.Multiply(0.5), Vec3d{0.5, -1.5, 1}},
+		{"Vec3d.Negative", Vec3d{1, -3, 0}.Negative(), Vec3d{-1, 3, 0}},
+		{"Vec3d.Dot", Vec3d{1, 2, 3}.Dot(Vec3d{4, 5, 6}), 32.0},
+		{"Vec3d.Cross", Vec3d{0, 0, 1}.Cross(Vec3d{1, 0, 0}), Vec3d{0, 1, 0}},
+		{"Vec3d.Length", Vec3d{2, 3, 6}.Length(), 7.0},
+		{"Vec3d.Distance", Vec3d{1, 1, 1}.Distance(Vec3d{3, 4, 7}), 7.0},
+		{"Vec3d.Normalize", Vec3d{0, -3, 0}.Normalize(), Vec3d{0, -1, 0}},
+		{"Vec3d.Normalize(zero)", Vec3d{}.Normalize(), Vec3d{}},
+		{"Vec3d.Lerp", Vec3d{0, 0, 0}.Lerp(Vec3d{4, -8, 2}, 0.5), Vec3d{2, -4, 1}},
+		{"Vec3d.Min", Vec3d{1, 5, 3}.Min(Vec3d{3, 2, 3}), Vec3d{1, 2, 3}},
+		{"Vec3d.Max", Vec3d{1, 5, 3}.Max(Vec3d{3, 2, 3}), Vec3d{3, 5, 3}},
+		{"Vec3d.Clamp", Vec3d{-1, 0.5, 2}.Clamp(Vec3d{0, 0, 0}, Vec3d{1, 1, 1}), Vec3d{0, 0.5, 1}},
+		{"Vec3d.Abs", Vec3d{-1.5, 2, 0}.Abs(), Vec3d{1.5, 2, 0}},
+		{"Vec3d.Floor", Vec3d{1.5, -1.5, 2}.Floor(), Vec3i{1, -2, 2}},
+		{"Vec3d.Ceiling", Vec3d{1.5, -1.5, 2}.Ceiling(), Vec3i{2, -1, 2}},
+		{"Vec3d.Round", Vec3d{1.4, -1.6, 2.5}.Round(), Vec3i{1, -2, 3}},
+		{"Vec3d.ToFloat32", Vec3d{1.5, -2, 3}.ToFloat32(), Vec3f{1.5, -2, 3}},
+
+		// Vec4d
+		{"Vec4d.Add", Vec4d{1, 2, 3, 4}.Add(Vec4d{0.5, 0.5, 0.5, 0.5}), Vec4d{1.5, 2.5, 3.5, 4.5}},
+		{"Vec4d.Sub", Vec4d{1, 2, 3, 4}.Sub(Vec4d{0.5, 0.5, 0.5, 0.5}), Vec4d{0.5, 1.5, 2.5, 3.5}},
+		{"Vec4d.Mul", Vec4d{1, 2, 3, 4}.Mul(Vec4d{2, 0.5, -1, 0}), Vec4d{2, 1, -3, 0}},
+		{"Vec4d.Div", Vec4d{1, 2, 3, 4}.Div(Vec4d{2, 4, -2, 8}), Vec4d{0.5, 0.5, -1.5, 0.5}},
+		{"Vec4d.Multiply", Vec4d{1, 2, 3, 4}.Multiply(-1), Vec4d{-1, -2, -3, -4}},
+		{"Vec4d.Negative", Vec4d{1, -2, 3, 0}.Negative(), Vec4d{-1, 2, -3, 0}},
+		{"Vec4d.Dot", Vec4d{1, 2, 3, 4}.Dot(Vec4d{4, 3, 2, 1}), 20.0},
+		{"Vec4d.Length", Vec4d{1, 1, 1, 1}.Length(), 2.0},
+		{"Vec4d.Distance", Vec4d{1, 1, 1, 1}.Distance(Vec4d{2, 2, 2, 2}), 2.0},
+		{"Vec4d.Normalize", Vec4d{2, 2, 2, 2}.Normalize(), Vec4d{0.5, 0.5, 0.5, 0.5}},
+		{"Vec4d.Normalize(zero)", Vec4d{}.Normalize(), Vec4d{}},
+		{"Vec4d.Lerp", Vec4d{0, 0, 0, 1}.Lerp(Vec4d{1, 2, 3, 1}, 0), Vec4d{0, 0, 0, 1}},
+		{"Vec4d.Min", Vec4d{1, 5, 3, -1}.Min(Vec4d{3, 2, 3, 0}), Vec4d{1, 2, 3, -1}},
+		{"Vec4d.Max", Vec4d{1, 5, 3, -1}.Max(Vec4d{3, 2, 3, 0}), Vec4d{3, 5, 3, 0}},
+		{"Vec4d.Clamp", Vec4d{-1, 0.5, 2, 1}.Clamp(Vec4d{}, Vec4d{1, 1, 1, 1}), Vec4d{0, 0.5, 1, 1}},
+		{"Vec4d.Abs", Vec4d{-1, 2, -3, 0}.Abs(), Vec4d{1, 2, 3, 0}},
+		{"Vec4d.Floor", Vec4d{1.5, -1.5, 2, -0.1}.Floor(), Vec4i{1, -2, 2, -1}},
+		{"Vec4d.Ceiling", Vec4d{1.5, -1.5, 2, -0.1}.Ceiling(), Vec4i{2, -1, 2, 0}},
+		{"Vec4d.Round", Vec4d{1.4, -1.6, 2.5, -2.5}.Round(), Vec4i{1, -2, 3, -3}},
+		{"Vec4d.ToFloat32", Vec4d{1.5, -2, 3, 4}.ToFloat32(), Vec4f{1.5, -2, 3, 4}},
+	})
+}
+
+func TestVecIntDivByZero(t *testing.T) {
+	// Integer division by zero panics, like the / operator
+	defer func() {
+		if recover() == nil {
+			t.Error("Vec2i.Div by zero did not panic")
+		}
+	}()
+	_ = Vec2i{1, 1}.Div(Vec2i{1, 0})
+}
+
+func TestVecFloatDivByZero(t *testing.T) {
+	got := Vec2d{1, -1}.Div(Vec2d{0, 0})
+	if !math.IsInf(got[0], 1) || !math.IsInf(got[1], -1) {
+		t.Errorf("Vec2d{1, -1}.Div(zero) = %v, want [+Inf -Inf]", got)
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"os"
 	"runtime"
+	"time"
 
 	"github.com/Edgaru089/imgui-go/v4"
 	"github.com/Edgaru089/implot-go-example/backend"
+	"github.com/Edgaru089/implot-go-example/render"
 	"github.com/go-gl/gl/all-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
@@ -12,11 +15,14 @@ import (
 const (
 	width  = 1024
 	height = 768
+
+	queueBudget = 4 * time.Millisecond // time spent each frame on GL work queued by other goroutines
 )
 
 var (
 	showDemoWindow = true
 	showImPlotDemo = true
+	showResources  = false
 )
 
 func init() {
@@ -48,6 +54,8 @@ func main() {
 	imgui.CreateContext(nil)
 
 	backend.Init(win)
+	defer render.ReportLeaks(os.Stderr)
+	defer backend.Shutdown()
 	win.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
 		backend.MouseButtonCallback(button, action)
 	})
@@ -67,10 +75,14 @@ func main() {
 	win.SwapBuffers()
 
 	for !win.ShouldClose() {
+		render.RunQueued(queueBudget)
 		backend.NewFrame()
 
 		imgui.ShowDemoWindow(&showDemoWindow)
 		imgui.ShowPlotDemoWindow(&showImPlotDemo)
+		if showResources {
+			backend.ShowResourcesWindow(&showResources)
+		}
 
 		example()
 
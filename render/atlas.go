@@ -0,0 +1,255 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// AtlasEntry is the location of one image in an Atlas.
+type AtlasEntry struct {
+	Rect itype.Recti // Rectangle in the texture, in pixels
+	UV   itype.Rectf // Rectangle in the texture, in texture coordinates
+}
+
+// UV0 returns the texture coordinates of the top-left corner, as used by imgui.Image.
+func (e AtlasEntry) UV0() itype.Vec2f {
+	return e.UV.MinPoint()
+}
+
+// UV1 returns the texture coordinates of the bottom-right corner, as used by imgui.Image.
+func (e AtlasEntry) UV1() itype.Vec2f {
+	return e.UV.MaxPoint()
+}
+
+// atlasShelf is one row of images in the atlas.
+type atlasShelf struct {
+	y, height int
+	x         int // where the next image goes
+}
+
+// atlasPacker is the layout of the shelves in a square texture.
+type atlasPacker struct {
+	size    int // the texture is size*size
+	padding int
+	shelves []atlasShelf
+}
+
+// Atlas packs many small images into a single RGBA Texture.
+//
+// Images are placed in rows (shelves) sorted by height. Adding an image that does not
+// fit anymore repacks everything into a larger texture, which moves the entries:
+// look them up again with Entry after adding images instead of keeping them.
+type Atlas struct {
+	tex     *Texture
+	pack    atlasPacker // layout of the texture as uploaded
+	maxSize int         // GL_MAX_TEXTURE_SIZE
+
+	images  map[string]image.Image
+	entries map[string]AtlasEntry
+}
+
+const atlasInitialSize = 256
+
+// NewAtlas creates a new, empty Atlas, with padding pixels of space kept between the images.
+func NewAtlas(padding int) *Atlas {
+	var maxSize int32
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &maxSize)
+
+	a := &Atlas{
+		pack:    atlasPacker{size: atlasInitialSize, padding: padding},
+		maxSize: int(maxSize),
+		images:  make(map[string]image.Image),
+		entries: make(map[string]AtlasEntry),
+	}
+	a.tex = NewTexture()
+	a.tex.UpdateData(FormatRGBA8, itype.Vec2i{atlasInitialSize, atlasInitialSize}, make([]byte, atlasInitialSize*atlasInitialSize*4))
+	return a
+}
+
+// place finds room for an image of the given size in the shelves, returning its position.
+func (p *atlasPacker) place(w, h int) (x, y int, ok bool) {
+	w, h = w+p.padding, h+p.padding
+
+	// Find the shortest shelf that is tall enough and has room left
+	best := -1
+	for i, s := range p.shelves {
+		if s.height >= h && p.size-s.x >= w && (best == -1 || s.height < p.shelves[best].height) {
+			best = i
+		}
+	}
+	if best != -1 {
+		s := &p.shelves[best]
+		x, y = s.x, s.y
+		s.x += w
+		return x, y, true
+	}
+
+	// Open a new shelf below the last one
+	var top int
+	if len(p.shelves) > 0 {
+		last := p.shelves[len(p.shelves)-1]
+		top = last.y + last.height
+	}
+	if top+h > p.size || w > p.size {
+		return 0, 0, false
+	}
+	p.shelves = append(p.shelves, atlasShelf{y: top, height: h, x: w})
+	return 0, top, true
+}
+
+// atlasEntry returns the entry of an image at (x, y) in a texture of the given size.
+func atlasEntry(img image.Image, x, y, size int) AtlasEntry {
+	b := img.Bounds()
+	return AtlasEntry{
+		Rect: itype.Recti{Left: x, Top: y, Width: b.Dx(), Height: b.Dy()},
+		UV: itype.Rectf{
+			Left:   float32(x) / float32(size),
+			Top:    float32(y) / float32(size),
+			Width:  float32(b.Dx()) / float32(size),
+			Height: float32(b.Dy()) / float32(size),
+		},
+	}
+}
+
+// Add adds an image to the atlas, replacing any image with the same name.
+//
+// The image is placed in the free space if possible; otherwise the atlas is repacked,
+// growing the texture if needed. An error is returned if it cannot fit at all,
+// and the atlas is left as it was (with any image of the same name still in it).
+func (a *Atlas) Add(name string, img image.Image) error {
+	oldImage, hadImage := a.images[name]
+	oldEntry, hadEntry := a.entries[name]
+
+	restore := func() {
+		if hadImage {
+			a.images[name] = oldImage
+		} else {
+			delete(a.images, name)
+		}
+		if hadEntry {
+			a.entries[name] = oldEntry
+		} else {
+			delete(a.entries, name)
+		}
+	}
+
+	// The old space is not reclaimed until the next repack
+	delete(a.entries, name)
+	a.images[name] = img
+
+	b := img.Bounds()
+	if x, y, ok := a.pack.place(b.Dx(), b.Dy()); ok {
+		e := atlasEntry(img, x, y, a.pack.size)
+		if b.Dx() > 0 && b.Dy() > 0 {
+			// Convert like Pack does, so that gray or paletted images
+			// are not uploaded as single-channel data into the RGBA texture
+			rgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+			draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+			if err := a.tex.UpdateRegion(e.Rect, rgba); err != nil {
+				restore()
+				return err
+			}
+		}
+		a.entries[name] = e
+		return nil
+	}
+
+	err := a.Pack()
+	if err != nil {
+		restore()
+	}
+	return err
+}
+
+// Remove removes an image from the atlas. Its space is reclaimed on the next repack.
+func (a *Atlas) Remove(name string) {
+	delete(a.images, name)
+	delete(a.entries, name)
+}
+
+// atlasLayout places the images tallest first, in the smallest square power-of-two texture
+// from minSize up to maxSize they fit in. ok is false if they do not fit at all.
+func atlasLayout(images map[string]image.Image, padding, minSize, maxSize int) (pack atlasPacker, entries map[string]AtlasEntry, ok bool) {
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		hi, hj := images[names[i]].Bounds().Dy(), images[names[j]].Bounds().Dy()
+		if hi != hj {
+			return hi > hj
+		}
+		return names[i] < names[j]
+	})
+
+	for size := minSize; size <= maxSize; size *= 2 {
+		pack = atlasPacker{size: size, padding: padding}
+		entries = make(map[string]AtlasEntry, len(names))
+
+		fits := true
+		for _, name := range names {
+			b := images[name].Bounds()
+			x, y, ok := pack.place(b.Dx(), b.Dy())
+			if !ok {
+				fits = false
+				break
+			}
+			entries[name] = atlasEntry(images[name], x, y, size)
+		}
+		if fits {
+			return pack, entries, true
+		}
+	}
+	return atlasPacker{}, nil, false
+}
+
+// Pack repacks all the images from scratch, tallest first, using the smallest
+// square power-of-two texture (of at least the current size) they fit in.
+//
+// If they do not fit in the largest texture supported, an error is returned
+// and the atlas is unchanged.
+func (a *Atlas) Pack() error {
+	pack, entries, ok := atlasLayout(a.images, a.pack.padding, a.pack.size, a.maxSize)
+	if !ok {
+		return fmt.Errorf("render: atlas images do not fit in a %dx%d texture", a.maxSize, a.maxSize)
+	}
+
+	// Upload everything in one go
+	canvas := image.NewNRGBA(image.Rect(0, 0, pack.size, pack.size))
+	for name, e := range entries {
+		img := a.images[name]
+		r := e.Rect
+		draw.Draw(canvas, image.Rect(r.Left, r.Top, r.Left+r.Width, r.Top+r.Height), img, img.Bounds().Min, draw.Src)
+	}
+	a.tex.UpdateImage(canvas)
+
+	a.pack = pack
+	a.entries = entries
+	return nil
+}
+
+// Entry returns the location of an image in the atlas.
+func (a *Atlas) Entry(name string) (e AtlasEntry, ok bool) {
+	e, ok = a.entries[name]
+	return
+}
+
+// Len returns the number of images in the atlas.
+func (a *Atlas) Len() int {
+	return len(a.images)
+}
+
+// Texture returns the atlas texture. It stays the same Texture across repacks.
+func (a *Atlas) Texture() *Texture {
+	return a.tex
+}
+
+// Free deletes the atlas texture.
+func (a *Atlas) Free() {
+	a.tex.Free()
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"image"
+	"testing"
+)
+
+func TestAtlasPackerPlace(t *testing.T) {
+	p := atlasPacker{size: 16, padding: 1}
+
+	tests := []struct {
+		name   string
+		w, h   int
+		x, y   int
+		placed bool
+	}{
+		{"first shelf", 4, 4, 0, 0, true},
+		{"same shelf", 4, 2, 5, 0, true},
+		{"too wide", 16, 1, 0, 0, false},
+		{"new shelf", 3, 8, 0, 5, true},
+		{"shortest shelf that fits", 2, 2, 10, 0, true},
+		{"first shelf full", 4, 4, 4, 5, true},
+		{"too tall", 2, 10, 0, 0, false},
+		{"last shelf", 2, 2, 13, 0, true},
+		{"empty", 0, 0, 9, 5, true},
+	}
+	for _, tt := range tests {
+		x, y, ok := p.place(tt.w, tt.h)
+		if ok != tt.placed || (ok && (x != tt.x || y != tt.y)) {
+			t.Errorf("%s: place(%d, %d) = %d, %d, %v, want %d, %d, %v", tt.name, tt.w, tt.h, x, y, ok, tt.x, tt.y, tt.placed)
+		}
+	}
+}
+
+func TestAtlasLayout(t *testing.T) {
+	images := map[string]image.Image{
+		"a": image.NewGray(image.Rect(0, 0, 10, 12)),
+		"b": image.NewGray(image.Rect(5, 5, 15, 10)),
+		"c": image.NewGray(image.Rect(0, 0, 20, 3)),
+		"d": image.NewGray(image.Rect(0, 0, 0, 0)),
+	}
+
+	pack, entries, ok := atlasLayout(images, 1, 16, 256)
+	if !ok || pack.size != 32 {
+		t.Fatalf("atlasLayout() = size %d, %v, want size 32", pack.size, ok)
+	}
+	if len(entries) != len(images) {
+		t.Fatalf("atlasLayout() placed %d of %d images", len(entries), len(images))
+	}
+	for name, e := range entries {
+		b := images[name].Bounds()
+		if e.Rect.Width != b.Dx() || e.Rect.Height != b.Dy() ||
+			e.Rect.Left < 0 || e.Rect.Top < 0 || e.Rect.Left+e.Rect.Width > 32 || e.Rect.Top+e.Rect.Height > 32 {
+			t.Errorf("%s: entry %v, out of the texture or wrongly sized", name, e.Rect)
+		}
+		if e.UV.Left != float32(e.Rect.Left)/32 || e.UV.Height != float32(e.Rect.Height)/32 {
+			t.Errorf("%s: UV %v does not match %v", name, e.UV, e.Rect)
+		}
+		for other, o := range entries {
+			if other != name && e.Rect.Width > 0 && o.Rect.Width > 0 && e.Rect.Overlaps(o.Rect) {
+				t.Errorf("%s %v overlaps %s %v", name, e.Rect, other, o.Rect)
+			}
+		}
+	}
+
+	if _, _, ok := atlasLayout(images, 1, 16, 16); ok {
+		t.Error("atlasLayout() fit into a texture too small")
+	}
+}
+
+func TestAtlasAddRollback(t *testing.T) {
+	small := image.NewGray(image.Rect(0, 0, 8, 8))
+	huge := image.NewGray(image.Rect(0, 0, 64, 64))
+
+	// Adding images too large for maxSize fails before any GL call
+	a := &Atlas{
+		pack:    atlasPacker{size: 16},
+		maxSize: 32,
+		images:  map[string]image.Image{"a": small},
+		entries: map[string]AtlasEntry{"a": atlasEntry(small, 0, 0, 16)},
+	}
+	a.pack.place(8, 8)
+	pack := a.pack
+
+	if err := a.Add("a", huge); err == nil {
+		t.Fatal("Add() of an image too large succeeded")
+	}
+	if a.images["a"] != small {
+		t.Error("failed Add() replaced the image")
+	}
+	if e, ok := a.Entry("a"); !ok || e != atlasEntry(small, 0, 0, 16) {
+		t.Errorf("failed Add() changed the entry to %v, %v", e, ok)
+	}
+
+	if err := a.Add("b", huge); err == nil {
+		t.Fatal("Add() of an image too large succeeded")
+	}
+	if _, ok := a.images["b"]; ok {
+		t.Error("failed Add() kept the new image")
+	}
+	if _, ok := a.Entry("b"); ok {
+		t.Error("failed Add() kept an entry for the new image")
+	}
+
+	if a.Len() != 1 || a.pack.size != pack.size || len(a.pack.shelves) != len(pack.shelves) {
+		t.Errorf("failed Add() changed the atlas: %d images, layout %+v, was %+v", a.Len(), a.pack, pack)
+	}
+}
@@ -0,0 +1,190 @@
+package render
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// BufferUsage is the usage hint of a Buffer.
+type BufferUsage uint32
+
+const (
+	UsageStatic  BufferUsage = gl.STATIC_DRAW  // Set once, drawn many times
+	UsageDynamic BufferUsage = gl.DYNAMIC_DRAW // Updated now and then, drawn many times
+	UsageStream  BufferUsage = gl.STREAM_DRAW  // Updated every time it is drawn
+)
+
+// Buffer holds handle to an OpenGL Buffer object with vertex or index data.
+type Buffer struct {
+	buf    uint32
+	target uint32 // ARRAY_BUFFER or ELEMENT_ARRAY_BUFFER
+	usage  BufferUsage
+
+	size      int    // size in bytes
+	indexType uint32 // type of the indices, for index buffers
+	indexSize int    // size of one index in bytes
+}
+
+// NewVertexBuffer creates a new, empty Buffer for vertex attributes.
+func NewVertexBuffer(usage BufferUsage) *Buffer {
+	b := &Buffer{target: gl.ARRAY_BUFFER, usage: usage}
+	gl.GenBuffers(1, &b.buf)
+	track(ResourceBuffer, b.buf, b)
+	return b
+}
+
+// NewIndexBuffer creates a new, empty Buffer for vertex indices.
+func NewIndexBuffer(usage BufferUsage) *Buffer {
+	b := &Buffer{target: gl.ELEMENT_ARRAY_BUFFER, usage: usage, indexType: gl.UNSIGNED_INT, indexSize: 4}
+	gl.GenBuffers(1, &b.buf)
+	track(ResourceBuffer, b.buf, b)
+	return b
+}
+
+// bufferBytes returns the pointer and size of buffer data.
+//
+// indexType and indexSize are set for unsigned integer slices.
+func bufferBytes(data interface{}) (ptr unsafe.Pointer, bytes int, indexType uint32, indexSize int, err error) {
+	var n, elem int
+	switch d := data.(type) {
+	case []float32:
+		n, elem = len(d), 4
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []itype.Vec2f:
+		n, elem = len(d), 8
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []itype.Vec3f:
+		n, elem = len(d), 12
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []itype.Vec4f:
+		n, elem = len(d), 16
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []int32:
+		n, elem = len(d), 4
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []uint8:
+		n, elem = len(d), 1
+		indexType, indexSize = gl.UNSIGNED_BYTE, 1
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []uint16:
+		n, elem = len(d), 2
+		indexType, indexSize = gl.UNSIGNED_SHORT, 2
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []uint32:
+		n, elem = len(d), 4
+		indexType, indexSize = gl.UNSIGNED_INT, 4
+		if n > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	default:
+		err = fmt.Errorf("render: unsupported buffer data type %T", data)
+	}
+	return ptr, n * elem, indexType, indexSize, err
+}
+
+// bindCopy binds the buffer to COPY_WRITE_BUFFER for uploading, returning a function
+// that restores the previous binding.
+//
+// COPY_WRITE_BUFFER is used so uploading never changes the index buffer of the current vertex array.
+func (b *Buffer) bindCopy() (restore func()) {
+	var last int32
+	gl.GetIntegerv(gl.COPY_WRITE_BUFFER_BINDING, &last)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, b.buf)
+	return func() { gl.BindBuffer(gl.COPY_WRITE_BUFFER, uint32(last)) }
+}
+
+// SetData replaces the content of the buffer with glBufferData.
+//
+// data can be a []float32, []itype.Vec2f, []itype.Vec3f, []itype.Vec4f or []int32 slice,
+// or for index buffers a []uint8, []uint16 or []uint32 slice (which sets the index type).
+func (b *Buffer) SetData(data interface{}) error {
+	ptr, bytes, indexType, indexSize, err := bufferBytes(data)
+	if err != nil {
+		return err
+	}
+	if b.target == gl.ELEMENT_ARRAY_BUFFER {
+		if indexType == 0 {
+			return fmt.Errorf("render: index buffer data must be unsigned integers, not %T", data)
+		}
+		b.indexType, b.indexSize = indexType, indexSize
+	}
+
+	b.SetDataRaw(ptr, bytes)
+	return nil
+}
+
+// SetDataRaw replaces the content of the buffer with size bytes from ptr.
+// The index type of an index buffer is unchanged.
+func (b *Buffer) SetDataRaw(ptr unsafe.Pointer, size int) {
+	defer b.bindCopy()()
+	gl.BufferData(gl.COPY_WRITE_BUFFER, size, ptr, uint32(b.usage))
+	b.size = size
+}
+
+// SetSubData updates part of the buffer starting from offset (in bytes) with glBufferSubData.
+// data is like in SetData, and must fit in the buffer.
+func (b *Buffer) SetSubData(offset int, data interface{}) error {
+	ptr, bytes, indexType, _, err := bufferBytes(data)
+	if err != nil {
+		return err
+	}
+	if b.target == gl.ELEMENT_ARRAY_BUFFER && indexType != b.indexType {
+		return fmt.Errorf("render: index buffer sub data type %T does not match the buffer", data)
+	}
+	if offset < 0 || offset+bytes > b.size {
+		return fmt.Errorf("render: buffer sub data [%d, %d) out of buffer size %d", offset, offset+bytes, b.size)
+	}
+
+	defer b.bindCopy()()
+	gl.BufferSubData(gl.COPY_WRITE_BUFFER, offset, bytes, ptr)
+	return nil
+}
+
+// Size returns the size of the buffer in bytes.
+func (b *Buffer) Size() int {
+	return b.size
+}
+
+// IndexCount returns the number of indices in an index buffer.
+func (b *Buffer) IndexCount() int {
+	if b.indexSize == 0 {
+		return 0
+	}
+	return b.size / b.indexSize
+}
+
+// memory returns the size of the buffer.
+func (b *Buffer) memory() int {
+	return b.size
+}
+
+// Handle returns the OpenGL handle of the buffer.
+func (b *Buffer) Handle() uint32 {
+	return b.buf
+}
+
+// Free deletes the buffer.
+func (b *Buffer) Free() {
+	if b.buf != 0 {
+		untrack(ResourceBuffer, b.buf)
+		gl.DeleteBuffers(1, &b.buf)
+		b.buf = 0
+	}
+}
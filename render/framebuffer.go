@@ -0,0 +1,326 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// FramebufferOptions describes the attachments of a Framebuffer.
+type FramebufferOptions struct {
+	Depth   bool // Attach a depth buffer
+	Stencil bool // Attach a stencil buffer (a combined depth/stencil buffer if Depth is also set)
+
+	// DepthTexture makes the depth attachment a Texture instead of a renderbuffer,
+	// so it can be sampled afterwards. Ignored when Samples > 1 or Stencil is set.
+	DepthTexture bool
+
+	// Samples is the number of MSAA samples. If it is more than 1, drawing goes
+	// into multisampled renderbuffers and is resolved into the color texture on Unbind.
+	Samples int32
+}
+
+// Framebuffer is an OpenGL Framebuffer Object with a color Texture attached,
+// used to render into a texture (which can then be shown with imgui.Image).
+//
+// The color texture is upside down in imgui terms: use UV (0, 1) to (1, 0)
+// to show it upright.
+type Framebuffer struct {
+	opt  FramebufferOptions
+	size itype.Vec2i
+
+	fbo          uint32   // framebuffer holding color (and the depth texture)
+	color        *Texture // color attachment, the resolve target if multisampled
+	depth        *Texture // depth attachment, if opt.DepthTexture
+	depthRbo     uint32   // depth/stencil renderbuffer, if not a texture
+	msFbo        uint32   // multisampled framebuffer, if opt.Samples > 1
+	msColorRbo   uint32   // multisampled color renderbuffer
+	msDepthRbo   uint32   // multisampled depth/stencil renderbuffer
+	bound        bool
+	lastDraw     int32    // draw framebuffer binding before Bind
+	lastRead     int32    // read framebuffer binding before Bind
+	lastViewport [4]int32 // viewport before Bind
+}
+
+// NewFramebuffer creates a new Framebuffer of the given size.
+func NewFramebuffer(size itype.Vec2i, opt FramebufferOptions) (f *Framebuffer, err error) {
+	f = &Framebuffer{opt: opt}
+	if f.opt.Samples > 1 || f.opt.Stencil {
+		f.opt.DepthTexture = false
+	}
+
+	err = f.create(size)
+	if err != nil {
+		f.Free()
+		return nil, err
+	}
+	return
+}
+
+func depthStencilFormat(opt FramebufferOptions) (internal uint32, attachment uint32) {
+	switch {
+	case opt.Depth && opt.Stencil:
+		return gl.DEPTH24_STENCIL8, gl.DEPTH_STENCIL_ATTACHMENT
+	case opt.Stencil:
+		return gl.STENCIL_INDEX8, gl.STENCIL_ATTACHMENT
+	default:
+		return gl.DEPTH_COMPONENT24, gl.DEPTH_ATTACHMENT
+	}
+}
+
+// depthStencilBytes returns the bytes per pixel of the depth/stencil buffer.
+func depthStencilBytes(opt FramebufferOptions) int {
+	if !opt.Depth {
+		return 1
+	}
+	return 4
+}
+
+func checkFramebuffer(target uint32) error {
+	status := gl.CheckFramebufferStatus(target)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("framebuffer incomplete (status 0x%x)", status)
+	}
+	return nil
+}
+
+// create creates all the GL objects of the framebuffer at the given size.
+func (f *Framebuffer) create(size itype.Vec2i) error {
+	if size[0] <= 0 || size[1] <= 0 {
+		return errors.New("framebuffer size must be positive")
+	}
+	f.size = size
+	w, h := int32(size[0]), int32(size[1])
+
+	var lastFbo, lastRbo int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &lastFbo)
+	gl.GetIntegerv(gl.RENDERBUFFER_BINDING, &lastRbo)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(lastFbo))
+	defer gl.BindRenderbuffer(gl.RENDERBUFFER, uint32(lastRbo))
+
+	// Resolve (or only) framebuffer with the color texture
+	f.color = NewTexture()
+	f.color.SetSmooth(true)
+	f.color.UpdateData(FormatRGBA8, size, nil)
+
+	gl.GenFramebuffers(1, &f.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.fbo)
+	track(ResourceFramebuffer, f.fbo, f)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, f.color.tex, 0)
+
+	if f.opt.Samples <= 1 && (f.opt.Depth || f.opt.Stencil) {
+		internal, attachment := depthStencilFormat(f.opt)
+		if f.opt.DepthTexture {
+			f.depth, _ = NewTextureData(FormatDepth, size, nil)
+			gl.FramebufferTexture2D(gl.FRAMEBUFFER, attachment, gl.TEXTURE_2D, f.depth.tex, 0)
+		} else {
+			gl.GenRenderbuffers(1, &f.depthRbo)
+			gl.BindRenderbuffer(gl.RENDERBUFFER, f.depthRbo)
+			gl.RenderbufferStorage(gl.RENDERBUFFER, internal, w, h)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, attachment, gl.RENDERBUFFER, f.depthRbo)
+		}
+	}
+	if err := checkFramebuffer(gl.FRAMEBUFFER); err != nil {
+		return err
+	}
+
+	if f.opt.Samples > 1 {
+		// Multisampled framebuffer drawn into, resolved into f.fbo
+		gl.GenFramebuffers(1, &f.msFbo)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, f.msFbo)
+
+		gl.GenRenderbuffers(1, &f.msColorRbo)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, f.msColorRbo)
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, f.opt.Samples, gl.RGBA8, w, h)
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, f.msColorRbo)
+
+		if f.opt.Depth || f.opt.Stencil {
+			internal, attachment := depthStencilFormat(f.opt)
+			gl.GenRenderbuffers(1, &f.msDepthRbo)
+			gl.BindRenderbuffer(gl.RENDERBUFFER, f.msDepthRbo)
+			gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, f.opt.Samples, internal, w, h)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, attachment, gl.RENDERBUFFER, f.msDepthRbo)
+		}
+		if err := checkFramebuffer(gl.FRAMEBUFFER); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// destroy deletes all the GL objects of the framebuffer.
+func (f *Framebuffer) destroy() {
+	if f.color != nil {
+		f.color.Free()
+		f.color = nil
+	}
+	if f.depth != nil {
+		f.depth.Free()
+		f.depth = nil
+	}
+	for _, rbo := range []*uint32{&f.depthRbo, &f.msColorRbo, &f.msDepthRbo} {
+		if *rbo != 0 {
+			gl.DeleteRenderbuffers(1, rbo)
+			*rbo = 0
+		}
+	}
+	if f.fbo != 0 {
+		untrack(ResourceFramebuffer, f.fbo)
+	}
+	for _, fbo := range []*uint32{&f.fbo, &f.msFbo} {
+		if *fbo != 0 {
+			gl.DeleteFramebuffers(1, fbo)
+			*fbo = 0
+		}
+	}
+}
+
+// memory estimates the GPU memory used by the renderbuffers.
+// The textures are accounted for by themselves.
+func (f *Framebuffer) memory() int {
+	pixels := f.size[0] * f.size[1]
+	var m int
+	if f.depthRbo != 0 {
+		m += pixels * depthStencilBytes(f.opt)
+	}
+	if f.msColorRbo != 0 {
+		m += pixels * 4 * int(f.opt.Samples)
+	}
+	if f.msDepthRbo != 0 {
+		m += pixels * depthStencilBytes(f.opt) * int(f.opt.Samples)
+	}
+	return m
+}
+
+// Resize recreates the attachments at a new size, discarding the contents.
+// It does nothing if the size is unchanged. If it fails, the framebuffer
+// keeps its old size and attachments.
+//
+// The framebuffer must not be bound.
+func (f *Framebuffer) Resize(size itype.Vec2i) error {
+	if size == f.size && f.fbo != 0 {
+		return nil
+	}
+
+	// Create the new attachments aside, and only replace the old ones on success
+	n := &Framebuffer{opt: f.opt}
+	if err := n.create(size); err != nil {
+		n.destroy()
+		return err
+	}
+	f.destroy()
+	f.size = n.size
+	f.fbo, f.color, f.depth, f.depthRbo = n.fbo, n.color, n.depth, n.depthRbo
+	f.msFbo, f.msColorRbo, f.msDepthRbo = n.msFbo, n.msColorRbo, n.msDepthRbo
+	track(ResourceFramebuffer, f.fbo, f) // Owned by f now
+	return nil
+}
+
+// Size returns the size of the framebuffer in pixels.
+func (f *Framebuffer) Size() itype.Vec2i {
+	return f.size
+}
+
+// Bind binds the framebuffer for drawing and sets the viewport to cover it,
+// saving the previous framebuffer bindings and viewport.
+func (f *Framebuffer) Bind() {
+	if f.bound {
+		return
+	}
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &f.lastDraw)
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &f.lastRead)
+	gl.GetIntegerv(gl.VIEWPORT, &f.lastViewport[0])
+
+	if f.msFbo != 0 {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, f.msFbo)
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, f.fbo)
+	}
+	gl.Viewport(0, 0, int32(f.size[0]), int32(f.size[1]))
+	f.bound = true
+}
+
+// Unbind resolves the multisampled content if any, then restores the
+// framebuffer bindings and viewport saved by Bind.
+func (f *Framebuffer) Unbind() {
+	if !f.bound {
+		return
+	}
+	f.resolve()
+
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, uint32(f.lastDraw))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(f.lastRead))
+	gl.Viewport(f.lastViewport[0], f.lastViewport[1], f.lastViewport[2], f.lastViewport[3])
+	f.bound = false
+}
+
+// resolve blits the multisampled framebuffer into the color texture.
+//
+// It leaves the framebuffer bindings modified.
+func (f *Framebuffer) resolve() {
+	if f.msFbo == 0 {
+		return
+	}
+	w, h := int32(f.size[0]), int32(f.size[1])
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, f.msFbo)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, f.fbo)
+	gl.BlitFramebuffer(0, 0, w, h, 0, 0, w, h, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+}
+
+// Texture returns the color attachment. It is owned by the Framebuffer,
+// and is replaced on Resize.
+func (f *Framebuffer) Texture() *Texture {
+	return f.color
+}
+
+// DepthTexture returns the depth attachment if it was created with DepthTexture, or nil.
+func (f *Framebuffer) DepthTexture() *Texture {
+	return f.depth
+}
+
+// Handle returns the OpenGL handle of the framebuffer holding the color texture.
+func (f *Framebuffer) Handle() uint32 {
+	return f.fbo
+}
+
+// Image reads back the color attachment into a new image, the right way up.
+func (f *Framebuffer) Image() *image.RGBA {
+	w, h := f.size[0], f.size[1]
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	var lastRead, lastPack int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &lastRead)
+	gl.GetIntegerv(gl.PACK_ALIGNMENT, &lastPack)
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, f.fbo)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(w), int32(h), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, lastPack)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(lastRead))
+
+	flipRows(img.Pix, img.Stride, h)
+	return img
+}
+
+// flipRows flips rows of pixels vertically in place,
+// between OpenGL (bottom-up) and image (top-down) order.
+func flipRows(pix []byte, stride, height int) {
+	tmp := make([]byte, stride)
+	for i := 0; i < height/2; i++ {
+		top := pix[i*stride : (i+1)*stride]
+		bottom := pix[(height-1-i)*stride : (height-i)*stride]
+		copy(tmp, top)
+		copy(top, bottom)
+		copy(bottom, tmp)
+	}
+}
+
+// Free deletes the framebuffer and its attachments.
+func (f *Framebuffer) Free() {
+	f.destroy()
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+
+	// Image formats understood by LoadTexture
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	_ "golang.org/x/image/bmp"
+)
+
+// TextureLoad is a texture being loaded from an image file by LoadTexture.
+//
+// Its Texture is usable right away: it is a 1x1 transparent placeholder until
+// the image is decoded and uploaded, and then keeps the same OpenGL handle.
+type TextureLoad struct {
+	tex    *Texture
+	mipmap bool
+
+	err error
+	fin bool // set once uploaded (or failed), on the main thread
+}
+
+// Texture returns the texture, which is a placeholder until Ready.
+func (l *TextureLoad) Texture() *Texture {
+	return l.tex
+}
+
+// Ready returns true once the image is uploaded, or has failed to load.
+func (l *TextureLoad) Ready() bool {
+	return l.fin
+}
+
+// Err returns the error of a failed load, or nil.
+func (l *TextureLoad) Err() error {
+	return l.err
+}
+
+// LoadTexture starts loading a texture from an image file (PNG, JPEG, GIF or BMP).
+//
+// The file is decoded on a separate goroutine, and uploaded on the main thread
+// by RunQueued; mipmaps are generated if mipmap is set.
+//
+// LoadTexture itself creates the placeholder texture with NewTexture,
+// so it must be called on the main (GL) thread too.
+func LoadTexture(path string, mipmap bool) *TextureLoad {
+	return startLoad(func() (image.Image, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("render: decoding %s: %w", path, err)
+		}
+		return img, nil
+	}, mipmap)
+}
+
+// LoadTextureFS is like LoadTexture, reading the file from a fs.FS.
+func LoadTextureFS(fsys fs.FS, name string, mipmap bool) *TextureLoad {
+	return startLoad(func() (image.Image, error) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("render: decoding %s: %w", name, err)
+		}
+		return img, nil
+	}, mipmap)
+}
+
+func startLoad(decode func() (image.Image, error), mipmap bool) *TextureLoad {
+	l := &TextureLoad{tex: NewTexture(), mipmap: mipmap}
+	l.tex.UpdateData(FormatRGBA8, itype.Vec2i{1, 1}, []byte{0, 0, 0, 0})
+
+	go func() {
+		img, err := decode()
+		Do(func() {
+			if err == nil && l.tex.Handle() != 0 {
+				l.tex.UpdateImage(img)
+				if l.mipmap {
+					l.tex.GenerateMipMap()
+				}
+			}
+			l.err = err
+			l.fin = true
+		})
+	}()
+	return l
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OpenGL calls must all be made on the locked main thread. Other goroutines queue
+// their GL work with Do or DoSync, and the main loop runs it with RunQueued.
+
+// queuedTask is a function waiting in the queue.
+type queuedTask struct {
+	f     func()
+	state int32         // taskWaiting, taskRunning or taskCancelled
+	done  chan struct{} // closed after f has run, for DoSync
+
+	panicked   bool        // f panicked, set before done is closed
+	panicValue interface{} // what f panicked with
+}
+
+// run runs f. For DoSync tasks, a panic in f is recovered and handed to the waiter
+// (see wait) instead of unwinding the main thread.
+func (t *queuedTask) run() {
+	if t.done == nil {
+		t.f()
+		return
+	}
+
+	finished := false
+	defer func() {
+		if !finished {
+			t.panicked, t.panicValue = true, recover()
+		}
+	}()
+	t.f()
+	finished = true
+}
+
+// wait waits until done is closed, panicking again if f panicked.
+func (t *queuedTask) wait() {
+	<-t.done
+	if t.panicked {
+		panic(t.panicValue)
+	}
+}
+
+const (
+	taskWaiting int32 = iota
+	taskRunning
+	taskCancelled
+)
+
+var (
+	queueLock sync.Mutex
+	queue     []*queuedTask
+)
+
+func enqueue(t *queuedTask) {
+	queueLock.Lock()
+	queue = append(queue, t)
+	queueLock.Unlock()
+}
+
+// Do queues f to be run on the main thread by RunQueued, and returns immediately.
+// It is safe to call from any goroutine.
+func Do(f func()) {
+	enqueue(&queuedTask{f: f})
+}
+
+// DoSync queues f to be run on the main thread, and waits until it has run.
+// If f panics, the panic is raised again in the goroutine calling DoSync.
+//
+// It must not be called from the main thread itself, which would wait forever.
+func DoSync(f func()) {
+	DoSyncContext(context.Background(), f)
+}
+
+// DoSyncContext is like DoSync, but gives up when ctx is done.
+//
+// If ctx is done before f has started, f is never run and ctx.Err() is returned.
+// Once f has started, DoSyncContext waits for it to finish and returns nil.
+func DoSyncContext(ctx context.Context, f func()) error {
+	t := &queuedTask{f: f, done: make(chan struct{})}
+	enqueue(t)
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		if atomic.CompareAndSwapInt32(&t.state, taskWaiting, taskCancelled) {
+			return ctx.Err()
+		}
+		// Already running
+	}
+	t.wait()
+	return nil
+}
+
+// DoSyncTimeout is like DoSync, but gives up if f has not started after timeout.
+func DoSyncTimeout(timeout time.Duration, f func()) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return DoSyncContext(ctx, f)
+}
+
+// RunQueued runs the queued functions in order on the calling (main) thread,
+// until the queue is empty or budget has passed. At least one function is run
+// if any is queued, and the rest wait for the next call.
+//
+// A panic in a function queued with Do unwinds RunQueued as usual;
+// one queued with DoSync is handed over to the goroutine waiting for it.
+//
+// It returns the number of functions run.
+func RunQueued(budget time.Duration) (count int) {
+	start := time.Now()
+	for {
+		queueLock.Lock()
+		if len(queue) == 0 {
+			queueLock.Unlock()
+			return
+		}
+		t := queue[0]
+		queue[0] = nil
+		queue = queue[1:]
+		queueLock.Unlock()
+
+		if atomic.CompareAndSwapInt32(&t.state, taskWaiting, taskRunning) {
+			t.run()
+			count++
+		}
+		if t.done != nil {
+			close(t.done)
+		}
+
+		if time.Since(start) >= budget {
+			return
+		}
+	}
+}
+
+// QueueLen returns the number of functions waiting in the queue.
+func QueueLen() int {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+	return len(queue)
+}
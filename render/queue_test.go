@@ -0,0 +1,74 @@
+package render
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// runQueueUntil runs the queue on the calling goroutine until done is closed.
+func runQueueUntil(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			RunQueued(time.Second)
+			return
+		default:
+			RunQueued(time.Second)
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestQueueOrder(t *testing.T) {
+	var got []int
+	for i := 0; i < 5; i++ {
+		i := i
+		Do(func() { got = append(got, i) })
+	}
+	if n := QueueLen(); n != 5 {
+		t.Fatalf("QueueLen() = %d, want 5", n)
+	}
+	if n := RunQueued(time.Hour); n != 5 {
+		t.Fatalf("RunQueued() = %d, want 5", n)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("ran in order %v", got)
+		}
+	}
+}
+
+func TestDoSyncPanic(t *testing.T) {
+	done := make(chan struct{})
+	var recovered interface{}
+	go func() {
+		defer close(done)
+		defer func() { recovered = recover() }()
+		DoSync(func() { panic("boom") })
+	}()
+	runQueueUntil(done)
+
+	if recovered != "boom" {
+		t.Fatalf("DoSync caller recovered %v, want boom", recovered)
+	}
+}
+
+func TestDoSyncContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := false
+	errc := make(chan error)
+	go func() { errc <- DoSyncContext(ctx, func() { ran = true }) }()
+
+	// Wait for the task to be queued, then cancel it before it runs
+	for QueueLen() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Fatalf("DoSyncContext() = %v, want %v", err, context.Canceled)
+	}
+	if n := RunQueued(time.Hour); n != 0 || ran {
+		t.Fatalf("cancelled task ran (RunQueued() = %d)", n)
+	}
+}
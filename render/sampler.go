@@ -0,0 +1,83 @@
+package render
+
+import "github.com/go-gl/gl/all-core/gl"
+
+// WrapMode is how texture coordinates outside [0, 1] are handled.
+type WrapMode int32
+
+const (
+	WrapClampToEdge    WrapMode = gl.CLAMP_TO_EDGE   // Repeat the edge pixels; the default
+	WrapClampToBorder  WrapMode = gl.CLAMP_TO_BORDER // Use the border color
+	WrapRepeat         WrapMode = gl.REPEAT          // Tile the texture
+	WrapMirroredRepeat WrapMode = gl.MIRRORED_REPEAT // Tile the texture, mirroring every other tile
+)
+
+// Sampler holds handle to an OpenGL Sampler object.
+//
+// A Sampler bound to a texture unit overrides the sampling parameters of
+// the texture bound to the same unit, so one texture can be sampled in
+// different ways, and one Sampler can be reused with many textures.
+type Sampler struct {
+	sampler uint32
+}
+
+// NewSampler creates a new Sampler with NEAREST filtering and CLAMP_TO_EDGE wrapping,
+// the same defaults as NewTexture.
+func NewSampler() *Sampler {
+	var sampler uint32
+	gl.GenSamplers(1, &sampler)
+
+	gl.SamplerParameteri(sampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.SamplerParameteri(sampler, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.SamplerParameteri(sampler, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.SamplerParameteri(sampler, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.SamplerParameteri(sampler, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+
+	track(ResourceSampler, sampler, nil)
+	return &Sampler{sampler: sampler}
+}
+
+// SetSmooth sets the min/mag filters to LINEAR(smooth) or NEAREST(not smooth).
+//
+// If mipmap is true, the min filter also interpolates between mipmap levels.
+func (s *Sampler) SetSmooth(smooth, mipmap bool) {
+	if smooth {
+		if mipmap {
+			gl.SamplerParameteri(s.sampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+		} else {
+			gl.SamplerParameteri(s.sampler, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		}
+		gl.SamplerParameteri(s.sampler, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	} else {
+		if mipmap {
+			gl.SamplerParameteri(s.sampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST_MIPMAP_LINEAR)
+		} else {
+			gl.SamplerParameteri(s.sampler, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		}
+		gl.SamplerParameteri(s.sampler, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	}
+}
+
+// SetParameteri calls glSamplerParameteri for parameters not covered by other methods.
+func (s *Sampler) SetParameteri(param uint32, value int32) {
+	gl.SamplerParameteri(s.sampler, param, value)
+}
+
+// Bind binds the sampler to a texture unit.
+func (s *Sampler) Bind(unit uint32) {
+	gl.BindSampler(unit, s.sampler)
+}
+
+// Handle returns the OpenGL handle of the sampler.
+func (s *Sampler) Handle() uint32 {
+	return s.sampler
+}
+
+// Free deletes the sampler.
+func (s *Sampler) Free() {
+	if s.sampler != 0 {
+		untrack(ResourceSampler, s.sampler)
+		gl.DeleteSamplers(1, &s.sampler)
+		s.sampler = 0
+	}
+}
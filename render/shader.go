@@ -3,6 +3,7 @@ package render
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/Edgaru089/implot-go-example/itype"
@@ -21,7 +22,37 @@ func getMaxTextureUnits() int32 {
 type Shader struct {
 	prog     uint32
 	uniforms map[string]int32
-	textures map[int32]*Texture // maps uniform location to *Texture
+	textures map[int32]*shaderTexture // maps uniform location to texture slot
+
+	units      []int32 // sorted uniform locations of textures, with units assigned
+	unitsDirty bool    // units need to be reassigned
+}
+
+// SamplerTexture is a texture that can be set on a sampler uniform:
+// a *Texture (sampler2D), *TextureArray (sampler2DArray) or *Texture3D (sampler3D).
+type SamplerTexture interface {
+	Handle() uint32
+	Target() uint32
+}
+
+// textureBindingQuery returns the glGet query of the binding of a texture target.
+func textureBindingQuery(target uint32) uint32 {
+	switch target {
+	case gl.TEXTURE_2D_ARRAY:
+		return gl.TEXTURE_BINDING_2D_ARRAY
+	case gl.TEXTURE_3D:
+		return gl.TEXTURE_BINDING_3D
+	default:
+		return gl.TEXTURE_BINDING_2D
+	}
+}
+
+// shaderTexture is a texture set on a sampler uniform.
+type shaderTexture struct {
+	tex      SamplerTexture
+	sampler  *Sampler
+	explicit bool  // unit is set explicitly by SetUniformTextureUnit
+	unit     int32 // texture unit, assigned by assignUnits if not explicit
 }
 
 // helper construct to get uniforms and restore previous glUseProgram
@@ -92,7 +123,7 @@ func NewShader(vert, frag string) (s *Shader, err error) {
 
 	s = &Shader{}
 	s.uniforms = make(map[string]int32)
-	s.textures = make(map[int32]*Texture)
+	s.textures = make(map[int32]*shaderTexture)
 	s.prog = gl.CreateProgram()
 
 	gl.AttachShader(s.prog, vertid)
@@ -118,6 +149,7 @@ func NewShader(vert, frag string) (s *Shader, err error) {
 	gl.DeleteShader(vertid)
 	gl.DeleteShader(fragid)
 
+	track(ResourceShader, s.prog, nil)
 	return
 }
 
@@ -144,20 +176,100 @@ func (s *Shader) UseProgram() {
 	gl.UseProgram(s.prog)
 }
 
-// BindTextures calls glActiveTexture and glBindTexture, updating the texture unit slots.
-func (s *Shader) BindTextures() {
-	var i int
-	for loc, tex := range s.textures {
+// assignUnits assigns texture units to textures not bound to an explicit unit.
+//
+// Units are handed out from 0 upwards, in the order of the uniform locations,
+// skipping over units that are explicitly used.
+func (s *Shader) assignUnits() {
+	if !s.unitsDirty {
+		return
+	}
+
+	s.units = s.units[:0]
+	used := make(map[int32]bool)
+	for loc, st := range s.textures {
+		s.units = append(s.units, loc)
+		if st.explicit {
+			used[st.unit] = true
+		}
+	}
+	sort.Slice(s.units, func(i, j int) bool { return s.units[i] < s.units[j] })
+
+	var next int32
+	for _, loc := range s.units {
+		st := s.textures[loc]
+		if st.explicit {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		st.unit = next
+		next++
+	}
+
+	s.unitsDirty = false
+}
+
+// BindTextures calls glActiveTexture, glBindTexture and glBindSampler, updating the texture unit slots.
+//
+// Textures are bound to units in the order of their uniform locations, starting from unit 0,
+// unless they were set with an explicit unit. The sampler uniforms are updated to match.
+//
+// The returned function restores the previous texture and sampler bindings
+// of the units touched, and the active texture unit.
+func (s *Shader) BindTextures() (restore func()) {
+	if s.prog == 0 {
+		return func() {}
+	}
+	s.assignUnits()
 
-		index := int32(i + 1)
+	var lastActive, lastProgram int32
+	gl.GetIntegerv(gl.ACTIVE_TEXTURE, &lastActive)
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &lastProgram)
+	if uint32(lastProgram) != s.prog {
+		gl.UseProgram(s.prog)
+	}
 
-		gl.Uniform1i(loc, index)
-		gl.ActiveTexture(uint32(gl.TEXTURE0 + index))
-		gl.BindTexture(gl.TEXTURE_2D, tex.tex)
-		i++
+	type saved struct {
+		unit             int32
+		target           uint32
+		texture, sampler int32
+	}
+	saves := make([]saved, 0, len(s.units))
+
+	for _, loc := range s.units {
+		st := s.textures[loc]
+
+		gl.ActiveTexture(uint32(gl.TEXTURE0 + st.unit))
+		sv := saved{unit: st.unit, target: st.tex.Target()}
+		gl.GetIntegerv(textureBindingQuery(sv.target), &sv.texture)
+		gl.GetIntegerv(gl.SAMPLER_BINDING, &sv.sampler)
+		saves = append(saves, sv)
+
+		gl.Uniform1i(loc, st.unit)
+		gl.BindTexture(sv.target, st.tex.Handle())
+		if st.sampler != nil {
+			gl.BindSampler(uint32(st.unit), st.sampler.sampler)
+		} else {
+			gl.BindSampler(uint32(st.unit), 0)
+		}
 	}
 
+	if uint32(lastProgram) != s.prog {
+		gl.UseProgram(uint32(lastProgram))
+	}
 	gl.ActiveTexture(gl.TEXTURE0)
+
+	return func() {
+		// Restore in reverse, so that the first binding wins if a unit is used twice
+		for i := len(saves) - 1; i >= 0; i-- {
+			gl.ActiveTexture(uint32(gl.TEXTURE0 + saves[i].unit))
+			gl.BindTexture(saves[i].target, uint32(saves[i].texture))
+			gl.BindSampler(uint32(saves[i].unit), uint32(saves[i].sampler))
+		}
+		gl.ActiveTexture(uint32(lastActive))
+	}
 }
 
 // Handle returns the OpenGL handle of the program.
@@ -165,10 +277,67 @@ func (s *Shader) Handle() uint32 {
 	return s.prog
 }
 
+// Free deletes the shader program. The textures set on it are not deleted.
+func (s *Shader) Free() {
+	if s.prog != 0 {
+		untrack(ResourceShader, s.prog)
+		gl.DeleteProgram(s.prog)
+		s.prog = 0
+	}
+}
+
+// SetUniformTexture sets a sampler2D uniform to the texture.
+// Its texture unit is assigned automatically by BindTextures.
+// A nil texture removes the one set, like RemoveUniformTexture.
 func (s *Shader) SetUniformTexture(name string, tex *Texture) {
+	s.setUniformTexture(name, tex, false, 0)
+}
+
+// SetUniformTextureArray sets a sampler2DArray uniform to the array texture.
+// Its texture unit is assigned automatically by BindTextures.
+func (s *Shader) SetUniformTextureArray(name string, tex *TextureArray) {
+	s.setUniformTexture(name, tex, false, 0)
+}
+
+// SetUniformTexture3D sets a sampler3D uniform to the 3D texture.
+// Its texture unit is assigned automatically by BindTextures.
+func (s *Shader) SetUniformTexture3D(name string, tex *Texture3D) {
+	s.setUniformTexture(name, tex, false, 0)
+}
+
+// SetUniformTextureUnit sets a sampler uniform to the texture (of any kind),
+// which is always bound to the given texture unit.
+//
+// If the unit is out of range or already set explicitly on another uniform,
+// a warning is logged and the unit is assigned automatically instead.
+func (s *Shader) SetUniformTextureUnit(name string, tex SamplerTexture, unit int32) {
+	s.setUniformTexture(name, tex, true, unit)
+}
+
+// isNilTexture reports if tex is nil, or a nil pointer to one of the texture types.
+func isNilTexture(tex SamplerTexture) bool {
+	switch t := tex.(type) {
+	case nil:
+		return true
+	case *Texture:
+		return t == nil
+	case *TextureArray:
+		return t == nil
+	case *Texture3D:
+		return t == nil
+	}
+	return false
+}
+
+func (s *Shader) setUniformTexture(name string, tex SamplerTexture, explicit bool, unit int32) {
 	if s.prog == 0 {
 		return
 	}
+	if isNilTexture(tex) {
+		log.Printf("Shader: Warning: Nil texture set for \"%s\", removing it", name)
+		s.RemoveUniformTexture(name)
+		return
+	}
 
 	loc := s.UniformLocation(name)
 	if loc == -1 {
@@ -176,16 +345,67 @@ func (s *Shader) SetUniformTexture(name string, tex *Texture) {
 	}
 
 	// Store the location to texture map
-	_, ok := s.textures[loc]
+	st, ok := s.textures[loc]
 	if !ok {
 		// new texture, make sure there are enough texture units
-		if len(s.textures)+1 >= int(getMaxTextureUnits()) {
+		if len(s.textures) >= int(getMaxTextureUnits()) {
 			log.Printf("Shader: Warning: Impossible to use texture \"%s\" for shader: all available texture units are used", name)
 			return
 		}
+		st = &shaderTexture{}
+		s.textures[loc] = st
+	}
+
+	if explicit && (unit < 0 || unit >= getMaxTextureUnits()) {
+		log.Printf("Shader: Warning: Texture unit %d for \"%s\" out of range, assigning automatically", unit, name)
+		explicit, unit = false, 0
+	}
+	if explicit {
+		for other, ot := range s.textures {
+			if other != loc && ot.explicit && ot.unit == unit {
+				log.Printf("Shader: Warning: Texture unit %d for \"%s\" already used by another uniform, assigning automatically", unit, name)
+				explicit, unit = false, 0
+				break
+			}
+		}
 	}
 
-	s.textures[loc] = tex
+	st.tex = tex
+	st.explicit = explicit
+	if explicit {
+		st.unit = unit
+	}
+	s.unitsDirty = true
+}
+
+// SetUniformSampler sets the sampler object used together with the texture
+// of a sampler uniform, overriding the texture's own sampling parameters.
+//
+// The texture must be set first. A nil sampler reverts to the texture's own parameters.
+func (s *Shader) SetUniformSampler(name string, sampler *Sampler) {
+	if s.prog == 0 {
+		return
+	}
+
+	st, ok := s.textures[s.UniformLocation(name)]
+	if !ok {
+		log.Printf("Shader: Warning: Sampler set for \"%s\" without a texture", name)
+		return
+	}
+	st.sampler = sampler
+}
+
+// RemoveUniformTexture removes the texture set on a sampler uniform,
+// freeing its texture unit.
+func (s *Shader) RemoveUniformTexture(name string) {
+	loc, ok := s.uniforms[name]
+	if !ok {
+		return
+	}
+	if _, ok = s.textures[loc]; ok {
+		delete(s.textures, loc)
+		s.unitsDirty = true
+	}
 }
 
 // SetUniformTextureHandle sets a uniform as a sampler2D from an external OpenGL texture.
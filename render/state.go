@@ -0,0 +1,132 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// StateSnapshot is a copy of the parts of the OpenGL state commonly changed
+// by rendering code, taken with CaptureState and put back with Restore.
+//
+// Texture and sampler bindings are those of texture unit 0.
+type StateSnapshot struct {
+	Program       int32
+	ActiveTexture int32
+	Texture2D     int32
+	Sampler       int32
+
+	ArrayBuffer        int32
+	ElementArrayBuffer int32
+	VertexArray        int32
+	DrawFramebuffer    int32
+	ReadFramebuffer    int32
+
+	PolygonMode [2]int32
+	Viewport    [4]int32
+	ScissorBox  [4]int32
+
+	BlendSrcRGB        int32
+	BlendDstRGB        int32
+	BlendSrcAlpha      int32
+	BlendDstAlpha      int32
+	BlendEquationRGB   int32
+	BlendEquationAlpha int32
+
+	Blend       bool
+	CullFace    bool
+	DepthTest   bool
+	ScissorTest bool
+	StencilTest bool
+
+	FramebufferSRGB bool
+}
+
+// CaptureState reads the current OpenGL state into a StateSnapshot.
+func CaptureState() (s StateSnapshot) {
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &s.Program)
+	gl.GetIntegerv(gl.ACTIVE_TEXTURE, &s.ActiveTexture)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &s.Texture2D)
+	gl.GetIntegerv(gl.SAMPLER_BINDING, &s.Sampler)
+	gl.ActiveTexture(uint32(s.ActiveTexture))
+
+	gl.GetIntegerv(gl.ARRAY_BUFFER_BINDING, &s.ArrayBuffer)
+	gl.GetIntegerv(gl.ELEMENT_ARRAY_BUFFER_BINDING, &s.ElementArrayBuffer)
+	gl.GetIntegerv(gl.VERTEX_ARRAY_BINDING, &s.VertexArray)
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &s.DrawFramebuffer)
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &s.ReadFramebuffer)
+
+	gl.GetIntegerv(gl.POLYGON_MODE, &s.PolygonMode[0])
+	gl.GetIntegerv(gl.VIEWPORT, &s.Viewport[0])
+	gl.GetIntegerv(gl.SCISSOR_BOX, &s.ScissorBox[0])
+
+	gl.GetIntegerv(gl.BLEND_SRC_RGB, &s.BlendSrcRGB)
+	gl.GetIntegerv(gl.BLEND_DST_RGB, &s.BlendDstRGB)
+	gl.GetIntegerv(gl.BLEND_SRC_ALPHA, &s.BlendSrcAlpha)
+	gl.GetIntegerv(gl.BLEND_DST_ALPHA, &s.BlendDstAlpha)
+	gl.GetIntegerv(gl.BLEND_EQUATION_RGB, &s.BlendEquationRGB)
+	gl.GetIntegerv(gl.BLEND_EQUATION_ALPHA, &s.BlendEquationAlpha)
+
+	s.Blend = gl.IsEnabled(gl.BLEND)
+	s.CullFace = gl.IsEnabled(gl.CULL_FACE)
+	s.DepthTest = gl.IsEnabled(gl.DEPTH_TEST)
+	s.ScissorTest = gl.IsEnabled(gl.SCISSOR_TEST)
+	s.StencilTest = gl.IsEnabled(gl.STENCIL_TEST)
+	s.FramebufferSRGB = gl.IsEnabled(gl.FRAMEBUFFER_SRGB)
+	return
+}
+
+func setEnabled(cap uint32, enabled bool) {
+	if enabled {
+		gl.Enable(cap)
+	} else {
+		gl.Disable(cap)
+	}
+}
+
+// Restore sets the OpenGL state back to the snapshot.
+func (s StateSnapshot) Restore() {
+	gl.UseProgram(uint32(s.Program))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, uint32(s.Texture2D))
+	gl.BindSampler(0, uint32(s.Sampler))
+	gl.ActiveTexture(uint32(s.ActiveTexture))
+
+	// The element array buffer is part of the vertex array state
+	gl.BindVertexArray(uint32(s.VertexArray))
+	gl.BindBuffer(gl.ARRAY_BUFFER, uint32(s.ArrayBuffer))
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, uint32(s.ElementArrayBuffer))
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, uint32(s.DrawFramebuffer))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(s.ReadFramebuffer))
+
+	gl.PolygonMode(gl.FRONT_AND_BACK, uint32(s.PolygonMode[0]))
+	gl.Viewport(s.Viewport[0], s.Viewport[1], s.Viewport[2], s.Viewport[3])
+	gl.Scissor(s.ScissorBox[0], s.ScissorBox[1], s.ScissorBox[2], s.ScissorBox[3])
+
+	gl.BlendEquationSeparate(uint32(s.BlendEquationRGB), uint32(s.BlendEquationAlpha))
+	gl.BlendFuncSeparate(uint32(s.BlendSrcRGB), uint32(s.BlendDstRGB), uint32(s.BlendSrcAlpha), uint32(s.BlendDstAlpha))
+
+	setEnabled(gl.BLEND, s.Blend)
+	setEnabled(gl.CULL_FACE, s.CullFace)
+	setEnabled(gl.DEPTH_TEST, s.DepthTest)
+	setEnabled(gl.SCISSOR_TEST, s.ScissorTest)
+	setEnabled(gl.STENCIL_TEST, s.StencilTest)
+	setEnabled(gl.FRAMEBUFFER_SRGB, s.FramebufferSRGB)
+}
+
+// Diff returns a description of every field that differs between the two snapshots,
+// in the form "Field: this != other". It is empty if they are the same.
+//
+// It is meant for tests checking that some code leaves the state unchanged.
+func (s StateSnapshot) Diff(other StateSnapshot) (diff []string) {
+	a, b := reflect.ValueOf(s), reflect.ValueOf(other)
+	for i := 0; i < a.NumField(); i++ {
+		fa, fb := a.Field(i).Interface(), b.Field(i).Interface()
+		if fa != fb {
+			diff = append(diff, fmt.Sprintf("%s: %v != %v", a.Type().Field(i).Name, fa, fb))
+		}
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStateSnapshotDiff(t *testing.T) {
+	base := StateSnapshot{
+		Program:   3,
+		Viewport:  [4]int32{0, 0, 1280, 720},
+		Blend:     true,
+		Texture2D: 7,
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *StateSnapshot)
+		want   []string
+	}{
+		{"same", func(s *StateSnapshot) {}, nil},
+		{"int", func(s *StateSnapshot) { s.Program = 4 }, []string{"Program: 3 != 4"}},
+		{"array", func(s *StateSnapshot) { s.Viewport[2] = 640 }, []string{"Viewport: [0 0 1280 720] != [0 0 640 720]"}},
+		{"bool", func(s *StateSnapshot) { s.Blend = false }, []string{"Blend: true != false"}},
+		{
+			"several in field order",
+			func(s *StateSnapshot) { s.FramebufferSRGB = true; s.Texture2D = 0 },
+			[]string{"Texture2D: 7 != 0", "FramebufferSRGB: false != true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.change(&other)
+			if got := base.Diff(other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package render
 import (
 	"image"
 
+	"github.com/Edgaru089/implot-go-example/itype"
 	"github.com/go-gl/gl/all-core/gl"
 )
 
@@ -12,63 +13,65 @@ func curTextureBinding() uint32 {
 	return uint32(id)
 }
 
+// bind binds the texture, returning a function that restores the previous binding.
+func (t *Texture) bind() (restore func()) {
+	last := curTextureBinding()
+	gl.BindTexture(gl.TEXTURE_2D, t.tex)
+	return func() { gl.BindTexture(gl.TEXTURE_2D, last) }
+}
+
 // Texture holds handle to OpenGL Texture on the graphics card memory.
 type Texture struct {
 	tex uint32
 
+	size   itype.Vec2i
+	format TextureFormat
+
 	hasMipmap bool
 	smooth    bool
 }
 
 // NewTexture creates a new, empty Texture.
 func NewTexture() *Texture {
-	// Restore current texture binding
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-
 	var tex uint32
-
 	gl.GenTextures(1, &tex)
-	gl.BindTexture(gl.TEXTURE_2D, tex)
+	t := &Texture{tex: tex}
+
+	defer t.bind()()
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 
-	return &Texture{tex: tex}
+	track(ResourceTexture, tex, t)
+	return t
 }
 
 // NewTextureRGBA creates a new Texture with image.
 func NewTextureRGBA(image *image.RGBA) *Texture {
-	// Restore current texture binding
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-
-	var tex uint32
-
-	gl.GenTextures(1, &tex)
-	gl.BindTexture(gl.TEXTURE_2D, tex)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-
-	gl.TexImage2D(
-		gl.TEXTURE_2D,
-		0,
-		gl.RGBA,
-		int32(image.Rect.Size().X),
-		int32(image.Rect.Size().Y),
-		0,
-		gl.RGBA,
-		gl.UNSIGNED_BYTE,
-		gl.Ptr(image.Pix),
-	)
-
-	return &Texture{tex: tex}
+	return NewTextureImage(image)
 }
 
 // NewTextureFromHandle creates a new *Texture from an existing OpenGL handle.
+//
+// The size and format are queried from the texture; unknown formats are recorded as RGBA8.
 func NewTextureFromHandle(handle uint32) *Texture {
-	return &Texture{tex: handle}
+	t := &Texture{tex: handle}
+	defer t.bind()()
+
+	var w, h, internal int32
+	gl.GetTexLevelParameteriv(gl.TEXTURE_2D, 0, gl.TEXTURE_WIDTH, &w)
+	gl.GetTexLevelParameteriv(gl.TEXTURE_2D, 0, gl.TEXTURE_HEIGHT, &h)
+	gl.GetTexLevelParameteriv(gl.TEXTURE_2D, 0, gl.TEXTURE_INTERNAL_FORMAT, &internal)
+
+	t.size = itype.Vec2i{int(w), int(h)}
+	for f, info := range textureFormats {
+		if info.internal == internal {
+			t.format = TextureFormat(f)
+			break
+		}
+	}
+	return t
 }
 
 // updateFilters updates the MIN/MAG_FILTER parameters of the texture based on t.smooth and t.hasMipmap.
@@ -96,8 +99,7 @@ func (t *Texture) updateFilters() {
 
 // SetSmooth sets the min/mag filters to LINEAR(smooth) or NEAREST(not smooth)
 func (t *Texture) SetSmooth(smooth bool) {
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-	gl.BindTexture(gl.TEXTURE_2D, t.tex)
+	defer t.bind()()
 	t.smooth = smooth
 	t.updateFilters()
 }
@@ -105,35 +107,13 @@ func (t *Texture) SetSmooth(smooth bool) {
 // UpdateRGBA updates the content of the texture with image.
 // It deletes existing mipmap, you need to generate it again.
 func (t *Texture) UpdateRGBA(image *image.RGBA) {
-
-	// Restore current texture binding
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-
-	gl.BindTexture(gl.TEXTURE_2D, t.tex)
-	gl.TexImage2D(
-		gl.TEXTURE_2D,
-		0,
-		gl.RGBA,
-		int32(image.Rect.Size().X),
-		int32(image.Rect.Size().Y),
-		0,
-		gl.RGBA,
-		gl.UNSIGNED_BYTE,
-		gl.Ptr(image.Pix),
-	)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
-
-	t.hasMipmap = false
-	t.updateFilters()
+	t.UpdateImage(image)
 }
 
 // GenerateMipMap generates mipmap for the texture.
 func (t *Texture) GenerateMipMap() {
 
-	// Restore current texture binding
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-
-	gl.BindTexture(gl.TEXTURE_2D, t.tex)
+	defer t.bind()()
 	gl.GenerateMipmap(gl.TEXTURE_2D)
 
 	t.hasMipmap = true
@@ -143,24 +123,47 @@ func (t *Texture) GenerateMipMap() {
 // InvalidateMipMap invalidates mipmap for the texture.
 func (t *Texture) InvalidateMipMap() {
 
-	// Restore current texture binding
-	defer gl.BindTexture(gl.TEXTURE_2D, curTextureBinding())
-
-	gl.BindTexture(gl.TEXTURE_2D, t.tex)
+	defer t.bind()()
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 
 	t.hasMipmap = false
 	t.updateFilters()
 }
 
+// Size returns the size of the texture in pixels, as of the last full upload.
+func (t *Texture) Size() itype.Vec2i {
+	return t.size
+}
+
+// Format returns the pixel format of the texture.
+func (t *Texture) Format() TextureFormat {
+	return t.format
+}
+
 // Handle returns the OpenGL handle of the texture.
 func (t *Texture) Handle() uint32 {
 	return t.tex
 }
 
+// Target returns the OpenGL texture target, which is always TEXTURE_2D.
+func (t *Texture) Target() uint32 {
+	return gl.TEXTURE_2D
+}
+
 // Free deletes the texture.
 func (t *Texture) Free() {
 	if t.tex != 0 {
+		untrack(ResourceTexture, t.tex)
 		gl.DeleteTextures(1, &t.tex)
+		t.tex = 0
+	}
+}
+
+// memory estimates the GPU memory used by the texture.
+func (t *Texture) memory() int {
+	m := t.size[0] * t.size[1] * t.format.info().gpuSize
+	if t.hasMipmap {
+		m += m / 3
 	}
+	return m
 }
@@ -0,0 +1,408 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"unsafe"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// TextureFormat is the pixel format of a Texture.
+type TextureFormat int
+
+const (
+	FormatRGBA8   TextureFormat = iota // 8-bit RGBA, from []byte; the default
+	FormatSRGBA8                       // 8-bit RGBA in sRGB color space, from []byte
+	FormatR8                           // 8-bit single channel, from []byte
+	FormatRG8                          // 8-bit two channels, from []byte
+	FormatR16                          // 16-bit normalized single channel, from []uint16
+	FormatRGBA16F                      // 16-bit float RGBA, from []float32
+	FormatR32F                         // 32-bit float single channel, from []float32
+	FormatDepth                        // 32-bit float depth, from []float32
+)
+
+type textureFormatInfo struct {
+	internal  int32  // internal format
+	format    uint32 // client pixel format
+	xtype     uint32 // client component type
+	pixelSize int    // size of one client pixel in bytes
+	gpuSize   int    // size of one pixel in GPU memory in bytes, estimated
+}
+
+var textureFormats = [...]textureFormatInfo{
+	FormatRGBA8:   {gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, 4, 4},
+	FormatSRGBA8:  {gl.SRGB8_ALPHA8, gl.RGBA, gl.UNSIGNED_BYTE, 4, 4},
+	FormatR8:      {gl.R8, gl.RED, gl.UNSIGNED_BYTE, 1, 1},
+	FormatRG8:     {gl.RG8, gl.RG, gl.UNSIGNED_BYTE, 2, 2},
+	FormatR16:     {gl.R16, gl.RED, gl.UNSIGNED_SHORT, 2, 2},
+	FormatRGBA16F: {gl.RGBA16F, gl.RGBA, gl.FLOAT, 16, 8},
+	FormatR32F:    {gl.R32F, gl.RED, gl.FLOAT, 4, 4},
+	FormatDepth:   {gl.DEPTH_COMPONENT32F, gl.DEPTH_COMPONENT, gl.FLOAT, 4, 4},
+}
+
+func (f TextureFormat) info() textureFormatInfo {
+	if f < 0 || int(f) >= len(textureFormats) {
+		panic(fmt.Sprintf("render: invalid TextureFormat %d", int(f)))
+	}
+	return textureFormats[f]
+}
+
+// String returns the name of the format.
+func (f TextureFormat) String() string {
+	switch f {
+	case FormatRGBA8:
+		return "RGBA8"
+	case FormatSRGBA8:
+		return "SRGBA8"
+	case FormatR8:
+		return "R8"
+	case FormatRG8:
+		return "RG8"
+	case FormatR16:
+		return "R16"
+	case FormatRGBA16F:
+		return "RGBA16F"
+	case FormatR32F:
+		return "R32F"
+	case FormatDepth:
+		return "Depth"
+	default:
+		return fmt.Sprintf("TextureFormat(%d)", int(f))
+	}
+}
+
+// setUnpack sets the pixel unpack parameters for an upload,
+// returning a function restoring the previous ones.
+func setUnpack(rowLength int) (restore func()) {
+	var lastAlignment, lastRowLength int32
+	gl.GetIntegerv(gl.UNPACK_ALIGNMENT, &lastAlignment)
+	gl.GetIntegerv(gl.UNPACK_ROW_LENGTH, &lastRowLength)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, int32(rowLength))
+
+	return func() {
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, lastAlignment)
+		gl.PixelStorei(gl.UNPACK_ROW_LENGTH, lastRowLength)
+	}
+}
+
+// upload calls glTexImage2D on the bound texture, recording the size and format in t.
+//
+// rowLength is the length of a row in pixels in the data; 0 means the same as width.
+func (t *Texture) upload(format TextureFormat, width, height, rowLength int, data unsafe.Pointer) {
+	info := format.info()
+	defer setUnpack(rowLength)()
+
+	gl.TexImage2D(
+		gl.TEXTURE_2D,
+		0,
+		info.internal,
+		int32(width),
+		int32(height),
+		0,
+		info.format,
+		info.xtype,
+		data,
+	)
+
+	t.size = itype.Vec2i{width, height}
+	t.format = format
+}
+
+// uploadRegion calls glTexSubImage2D on the bound texture.
+//
+// The data is in the client layout of format, which can differ from the format of the texture.
+func (t *Texture) uploadRegion(rect itype.Recti, format TextureFormat, rowLength int, data unsafe.Pointer) {
+	info := format.info()
+	defer setUnpack(rowLength)()
+
+	gl.TexSubImage2D(
+		gl.TEXTURE_2D,
+		0,
+		int32(rect.Left),
+		int32(rect.Top),
+		int32(rect.Width),
+		int32(rect.Height),
+		info.format,
+		info.xtype,
+		data,
+	)
+}
+
+// dataPointer validates the size of data, returning a pointer to it.
+//
+// data can be nil, a []byte, []uint16 or []float32 slice, or an unsafe.Pointer
+// (which is not checked).
+func dataPointer(format TextureFormat, size itype.Vec2i, data interface{}) (unsafe.Pointer, error) {
+	var bytes int
+	var ptr unsafe.Pointer
+	switch d := data.(type) {
+	case nil:
+		return nil, nil
+	case unsafe.Pointer:
+		return d, nil
+	case []byte:
+		bytes = len(d)
+		if len(d) > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []uint16:
+		bytes = len(d) * 2
+		if len(d) > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	case []float32:
+		bytes = len(d) * 4
+		if len(d) > 0 {
+			ptr = unsafe.Pointer(&d[0])
+		}
+	default:
+		return nil, fmt.Errorf("render: unsupported texture data type %T", data)
+	}
+
+	if want := size[0] * size[1] * format.info().pixelSize; bytes < want {
+		return nil, fmt.Errorf("render: texture data too short for %dx%d %s: %d bytes, want %d", size[0], size[1], format, bytes, want)
+	}
+	return ptr, nil
+}
+
+// NewTextureData creates a new Texture of the given format and size from raw data.
+//
+// data can be nil (leaving the content undefined), a []byte, []uint16 or []float32
+// slice with at least size[0]*size[1] pixels packed tightly, or an unsafe.Pointer.
+// Rows go from the bottom of the texture to the top, as OpenGL expects.
+func NewTextureData(format TextureFormat, size itype.Vec2i, data interface{}) (*Texture, error) {
+	ptr, err := dataPointer(format, size, data)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTexture()
+
+	defer t.bind()()
+	t.upload(format, size[0], size[1], 0, ptr)
+
+	return t, nil
+}
+
+// UpdateData updates the content of the texture with raw data, like NewTextureData.
+// It deletes existing mipmap, you need to generate it again.
+func (t *Texture) UpdateData(format TextureFormat, size itype.Vec2i, data interface{}) error {
+	ptr, err := dataPointer(format, size, data)
+	if err != nil {
+		return err
+	}
+
+	defer t.bind()()
+	t.upload(format, size[0], size[1], 0, ptr)
+
+	t.hasMipmap = false
+	t.updateFilters()
+	return nil
+}
+
+// NewTextureImage creates a new Texture with any image.
+//
+// *image.NRGBA, *image.Gray and *image.Gray16 are uploaded directly
+// (the gray ones as single-channel textures, swizzled with SwizzleGray);
+// other images are converted to NRGBA first. Textures always hold straight
+// (non-premultiplied) alpha, so *image.RGBA is only uploaded directly if it is opaque.
+func NewTextureImage(img image.Image) *Texture {
+	t := NewTexture()
+	t.UpdateImage(img)
+	return t
+}
+
+// UpdateImage updates the content of the texture with any image, like NewTextureImage.
+// It deletes existing mipmap, you need to generate it again.
+//
+// The swizzle is set to SwizzleGray or SwizzleIdentity only when the image switches
+// the texture between gray and color formats, so one set by SetSwizzle is otherwise kept.
+func (t *Texture) UpdateImage(img image.Image) {
+
+	defer t.bind()()
+	wasGray := t.format == FormatR8 || t.format == FormatR16
+	format, rowLength, ptr := imagePixels(img)
+	size := img.Bounds().Size()
+	t.upload(format, size.X, size.Y, rowLength, ptr)
+
+	// Show gray images as gray instead of red
+	if gray := format == FormatR8 || format == FormatR16; gray != wasGray {
+		if gray {
+			gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &SwizzleGray[0])
+		} else {
+			gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &SwizzleIdentity[0])
+		}
+	}
+
+	t.hasMipmap = false
+	t.updateFilters()
+}
+
+// imagePixels returns the pixels of any image in a form OpenGL can upload.
+//
+// Opaque RGBA, NRGBA and Gray images are used in place, with rowLength set to their stride;
+// other images are converted into a new tightly-packed buffer.
+func imagePixels(img image.Image) (format TextureFormat, rowLength int, ptr unsafe.Pointer) {
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return FormatRGBA8, 0, nil
+	}
+
+	switch i := img.(type) {
+	case *image.RGBA:
+		if !i.Opaque() {
+			// Premultiplied; converting to NRGBA divides the alpha out
+			conv := toNRGBA(img)
+			return FormatRGBA8, 0, gl.Ptr(conv.Pix)
+		}
+		return FormatRGBA8, i.Stride / 4, gl.Ptr(i.Pix[i.PixOffset(i.Rect.Min.X, i.Rect.Min.Y):])
+	case *image.NRGBA:
+		return FormatRGBA8, i.Stride / 4, gl.Ptr(i.Pix[i.PixOffset(i.Rect.Min.X, i.Rect.Min.Y):])
+	case *image.Gray:
+		return FormatR8, i.Stride, gl.Ptr(i.Pix[i.PixOffset(i.Rect.Min.X, i.Rect.Min.Y):])
+	case *image.Gray16:
+		// Gray16 is big-endian; OpenGL wants native uint16
+		pix := make([]uint16, size.X*size.Y)
+		for y := 0; y < size.Y; y++ {
+			row := i.Pix[i.PixOffset(i.Rect.Min.X, i.Rect.Min.Y+y):]
+			for x := 0; x < size.X; x++ {
+				pix[y*size.X+x] = uint16(row[2*x])<<8 | uint16(row[2*x+1])
+			}
+		}
+		return FormatR16, 0, gl.Ptr(pix)
+	default:
+		conv := toNRGBA(img)
+		return FormatRGBA8, 0, gl.Ptr(conv.Pix)
+	}
+}
+
+// UpdateRegion updates a rectangle of the texture with an image, with glTexSubImage2D.
+// The size of the texture is unchanged, and so is the mipmap (which becomes stale).
+//
+// The image is drawn starting from its Bounds().Min, and is clipped to the rectangle.
+// The rectangle must lie inside the texture; it uses the same row order as the uploads,
+// i.e., row 0 of an uploaded image is Top 0. If it does not, or it is empty after
+// clipping, nothing is uploaded and an error is returned.
+func (t *Texture) UpdateRegion(rect itype.Recti, img image.Image) error {
+	size := img.Bounds().Size()
+	if size.X < rect.Width {
+		rect.Width = size.X
+	}
+	if size.Y < rect.Height {
+		rect.Height = size.Y
+	}
+	if !t.regionValid(rect) {
+		return fmt.Errorf("render: region %v out of texture size %v", rect, t.size)
+	}
+
+	format, rowLength, ptr := imagePixels(img)
+	if rowLength == 0 {
+		rowLength = size.X
+	}
+	defer t.bind()()
+	t.uploadRegion(rect, format, rowLength, ptr)
+	return nil
+}
+
+// UpdateRegionData updates a rectangle of the texture with raw data, with glTexSubImage2D.
+//
+// The data is laid out tightly with rect.Width*rect.Height pixels, in the client layout
+// of format (which need not be the format of the texture), like NewTextureData.
+func (t *Texture) UpdateRegionData(rect itype.Recti, format TextureFormat, data interface{}) error {
+	ptr, err := dataPointer(format, itype.Vec2i{rect.Width, rect.Height}, data)
+	if err != nil {
+		return err
+	}
+	if !t.regionValid(rect) {
+		return fmt.Errorf("render: region %v out of texture size %v", rect, t.size)
+	}
+
+	defer t.bind()()
+	t.uploadRegion(rect, format, 0, ptr)
+	return nil
+}
+
+// regionValid returns if rect is non-empty and inside the texture.
+func (t *Texture) regionValid(rect itype.Recti) bool {
+	return rect.Width > 0 && rect.Height > 0 &&
+		rect.Left >= 0 && rect.Top >= 0 &&
+		rect.Left+rect.Width <= t.size[0] && rect.Top+rect.Height <= t.size[1]
+}
+
+// Image reads the content of the texture back into a new image, with glGetTexImage.
+// Rows are in the same order as uploaded (see Framebuffer.Image for rendered content).
+//
+// R8 textures return *image.Gray, R16 and depth textures *image.Gray16,
+// and others *image.NRGBA, with float values clamped to [0, 1].
+// An empty texture (like a new one) returns an empty *image.NRGBA.
+func (t *Texture) Image() image.Image {
+	w, h := t.size[0], t.size[1]
+	if w == 0 || h == 0 {
+		return image.NewNRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	var lastPack int32
+	gl.GetIntegerv(gl.PACK_ALIGNMENT, &lastPack)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	defer gl.PixelStorei(gl.PACK_ALIGNMENT, lastPack)
+
+	defer t.bind()()
+
+	switch t.format {
+	case FormatR8:
+		img := image.NewGray(image.Rect(0, 0, w, h))
+		gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+		return img
+	case FormatR16, FormatDepth:
+		pix := make([]uint16, w*h)
+		if t.format == FormatDepth {
+			gl.GetTexImage(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT, gl.UNSIGNED_SHORT, gl.Ptr(pix))
+		} else {
+			gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RED, gl.UNSIGNED_SHORT, gl.Ptr(pix))
+		}
+		img := image.NewGray16(image.Rect(0, 0, w, h))
+		for i, v := range pix {
+			img.Pix[2*i], img.Pix[2*i+1] = byte(v>>8), byte(v)
+		}
+		return img
+	default:
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+		return img
+	}
+}
+
+// toNRGBA converts any image into a tightly packed NRGBA image with bounds starting at (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	conv := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	if p, ok := img.(*image.Paletted); ok {
+		// Expand the palette once instead of converting every pixel
+		// Pixels are bytes, so entries past 256 cannot be used
+		var pal [256][4]byte
+		for i, c := range p.Palette {
+			if i >= len(pal) {
+				break
+			}
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			pal[i] = [4]byte{n.R, n.G, n.B, n.A}
+		}
+		for y := 0; y < b.Dy(); y++ {
+			src := p.Pix[p.PixOffset(b.Min.X, b.Min.Y+y):]
+			dst := conv.Pix[y*conv.Stride:]
+			for x := 0; x < b.Dx(); x++ {
+				copy(dst[4*x:4*x+4], pal[src[x]][:])
+			}
+		}
+		return conv
+	}
+
+	draw.Draw(conv, conv.Rect, img, b.Min, draw.Src)
+	return conv
+}
@@ -0,0 +1,271 @@
+package render
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// layeredTexture is the common part of TextureArray and Texture3D:
+// a stack of 2D layers (or slices) of the same size and format.
+type layeredTexture struct {
+	tex    uint32
+	target uint32 // TEXTURE_2D_ARRAY or TEXTURE_3D
+	bindq  uint32 // TEXTURE_BINDING_2D_ARRAY or TEXTURE_BINDING_3D
+
+	size   itype.Vec3i // width, height, and layer count (or depth)
+	format TextureFormat
+
+	hasMipmap bool
+	smooth    bool
+}
+
+// TextureArray is an OpenGL 2D array texture (sampler2DArray),
+// a stack of 2D layers sampled by their index.
+type TextureArray struct {
+	layeredTexture
+}
+
+// Texture3D is an OpenGL 3D texture (sampler3D),
+// a stack of 2D slices interpolated along the depth.
+type Texture3D struct {
+	layeredTexture
+}
+
+// NewTextureArray creates a new TextureArray of size[2] layers, with undefined content.
+func NewTextureArray(format TextureFormat, size itype.Vec3i) *TextureArray {
+	t := &TextureArray{layeredTexture{target: gl.TEXTURE_2D_ARRAY, bindq: gl.TEXTURE_BINDING_2D_ARRAY}}
+	t.create(format, size)
+	track(ResourceTextureArray, t.tex, t)
+	return t
+}
+
+// NewTexture3D creates a new Texture3D of size[2] slices deep, with undefined content.
+func NewTexture3D(format TextureFormat, size itype.Vec3i) *Texture3D {
+	t := &Texture3D{layeredTexture{target: gl.TEXTURE_3D, bindq: gl.TEXTURE_BINDING_3D}}
+	t.create(format, size)
+	track(ResourceTexture3D, t.tex, t)
+	return t
+}
+
+// bind binds the texture, returning a function that restores the previous binding.
+func (t *layeredTexture) bind() (restore func()) {
+	var last int32
+	gl.GetIntegerv(t.bindq, &last)
+	gl.BindTexture(t.target, t.tex)
+	return func() { gl.BindTexture(t.target, uint32(last)) }
+}
+
+func (t *layeredTexture) create(format TextureFormat, size itype.Vec3i) {
+	info := format.info()
+	t.size = size
+	t.format = format
+
+	gl.GenTextures(1, &t.tex)
+	defer t.bind()()
+
+	gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+
+	gl.TexImage3D(
+		t.target,
+		0,
+		info.internal,
+		int32(size[0]),
+		int32(size[1]),
+		int32(size[2]),
+		0,
+		info.format,
+		info.xtype,
+		nil,
+	)
+}
+
+// UpdateLayer updates one layer (or slice) with an image, starting from its Bounds().Min.
+// The image is clipped to the layer size. An error is returned, and nothing uploaded,
+// if the layer is out of range or the image is empty.
+func (t *layeredTexture) UpdateLayer(layer int, img image.Image) error {
+	if layer < 0 || layer >= t.size[2] {
+		return fmt.Errorf("render: layer %d out of range [0, %d)", layer, t.size[2])
+	}
+	size := img.Bounds().Size()
+	w, h := size.X, size.Y
+	if w > t.size[0] {
+		w = t.size[0]
+	}
+	if h > t.size[1] {
+		h = t.size[1]
+	}
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("render: empty image %v for layer %d", img.Bounds(), layer)
+	}
+
+	format, rowLength, ptr := imagePixels(img)
+	if rowLength == 0 {
+		rowLength = size.X
+	}
+	info := format.info()
+
+	defer t.bind()()
+	defer setUnpack(rowLength)()
+	gl.TexSubImage3D(t.target, 0, 0, 0, int32(layer), int32(w), int32(h), 1, info.format, info.xtype, ptr)
+	return nil
+}
+
+// UpdateLayerData updates one layer (or slice) with raw data, laid out like NewTextureData.
+func (t *layeredTexture) UpdateLayerData(layer int, format TextureFormat, data interface{}) error {
+	if layer < 0 || layer >= t.size[2] {
+		return fmt.Errorf("render: layer %d out of range [0, %d)", layer, t.size[2])
+	}
+	ptr, err := dataPointer(format, itype.Vec2i{t.size[0], t.size[1]}, data)
+	if err != nil {
+		return err
+	}
+	info := format.info()
+
+	defer t.bind()()
+	defer setUnpack(0)()
+	gl.TexSubImage3D(t.target, 0, 0, 0, int32(layer), int32(t.size[0]), int32(t.size[1]), 1, info.format, info.xtype, ptr)
+	return nil
+}
+
+// updateFilters updates the MIN/MAG_FILTER parameters, like Texture.updateFilters.
+func (t *layeredTexture) updateFilters() {
+	switch {
+	case t.smooth && t.hasMipmap:
+		gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+		gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	case t.smooth:
+		gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	case t.hasMipmap:
+		gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.NEAREST_MIPMAP_LINEAR)
+		gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	default:
+		gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(t.target, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	}
+}
+
+// SetSmooth sets the min/mag filters to LINEAR(smooth) or NEAREST(not smooth)
+func (t *layeredTexture) SetSmooth(smooth bool) {
+	defer t.bind()()
+	t.smooth = smooth
+	t.updateFilters()
+}
+
+// SetWrap sets the wrap mode of all coordinates.
+func (t *layeredTexture) SetWrap(mode WrapMode) {
+	defer t.bind()()
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_S, int32(mode))
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_T, int32(mode))
+	gl.TexParameteri(t.target, gl.TEXTURE_WRAP_R, int32(mode))
+}
+
+// SetSwizzle sets the swizzle mask of the texture.
+func (t *layeredTexture) SetSwizzle(swizzle Swizzle) {
+	defer t.bind()()
+	gl.TexParameteriv(t.target, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+}
+
+// GenerateMipMap generates mipmap for the texture.
+func (t *layeredTexture) GenerateMipMap() {
+	defer t.bind()()
+	gl.GenerateMipmap(t.target)
+	t.hasMipmap = true
+	t.updateFilters()
+}
+
+// CopyLayer copies one layer (or slice) into a 2D Texture, which can then be shown
+// with imgui.Image like any other texture, and returns it.
+//
+// If dst is nil, a new Texture is created. dst is resized to the layer size if needed.
+// Only color formats can be copied. It returns an error if the layer is out of range,
+// leaving dst untouched.
+func (t *layeredTexture) CopyLayer(layer int, dst *Texture) (*Texture, error) {
+	if layer < 0 || layer >= t.size[2] {
+		return dst, fmt.Errorf("render: layer %d out of range [0, %d)", layer, t.size[2])
+	}
+	if dst == nil {
+		dst = NewTexture()
+		dst.SetSmooth(t.smooth)
+	}
+	if dst.size != (itype.Vec2i{t.size[0], t.size[1]}) || dst.format != t.format {
+		dst.UpdateData(t.format, itype.Vec2i{t.size[0], t.size[1]}, nil)
+	}
+
+	var lastRead, lastDraw int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &lastRead)
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &lastDraw)
+
+	// The blit is clipped by the scissor test and converted by FRAMEBUFFER_SRGB
+	lastScissor := gl.IsEnabled(gl.SCISSOR_TEST)
+	lastSRGB := gl.IsEnabled(gl.FRAMEBUFFER_SRGB)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Disable(gl.FRAMEBUFFER_SRGB)
+
+	var fbos [2]uint32
+	gl.GenFramebuffers(2, &fbos[0])
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fbos[0])
+	gl.FramebufferTextureLayer(gl.READ_FRAMEBUFFER, gl.COLOR_ATTACHMENT0, t.tex, 0, int32(layer))
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, fbos[1])
+	gl.FramebufferTexture2D(gl.DRAW_FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, dst.tex, 0)
+
+	w, h := int32(t.size[0]), int32(t.size[1])
+	gl.BlitFramebuffer(0, 0, w, h, 0, 0, w, h, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(lastRead))
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, uint32(lastDraw))
+	gl.DeleteFramebuffers(2, &fbos[0])
+	setEnabled(gl.SCISSOR_TEST, lastScissor)
+	setEnabled(gl.FRAMEBUFFER_SRGB, lastSRGB)
+
+	return dst, nil
+}
+
+// Size returns the width, height, and layer count (or depth) of the texture.
+func (t *layeredTexture) Size() itype.Vec3i {
+	return t.size
+}
+
+// Format returns the pixel format of the texture.
+func (t *layeredTexture) Format() TextureFormat {
+	return t.format
+}
+
+// Handle returns the OpenGL handle of the texture.
+func (t *layeredTexture) Handle() uint32 {
+	return t.tex
+}
+
+// Target returns the OpenGL texture target (TEXTURE_2D_ARRAY or TEXTURE_3D).
+func (t *layeredTexture) Target() uint32 {
+	return t.target
+}
+
+// Free deletes the texture.
+func (t *layeredTexture) Free() {
+	if t.tex != 0 {
+		if t.target == gl.TEXTURE_3D {
+			untrack(ResourceTexture3D, t.tex)
+		} else {
+			untrack(ResourceTextureArray, t.tex)
+		}
+		gl.DeleteTextures(1, &t.tex)
+		t.tex = 0
+	}
+}
+
+// memory estimates the GPU memory used by the texture.
+func (t *layeredTexture) memory() int {
+	m := t.size[0] * t.size[1] * t.size[2] * t.format.info().gpuSize
+	if t.hasMipmap {
+		m += m / 3
+	}
+	return m
+}
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"github.com/Edgaru089/implot-go-example/itype"
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// Swizzle maps the channels of a texture when it is sampled.
+//
+// Each element is the source of the R, G, B and A channels respectively:
+// one of gl.RED, gl.GREEN, gl.BLUE, gl.ALPHA, gl.ZERO or gl.ONE.
+type Swizzle [4]int32
+
+var (
+	SwizzleIdentity  = Swizzle{gl.RED, gl.GREEN, gl.BLUE, gl.ALPHA} // Channels as they are; the default
+	SwizzleGray      = Swizzle{gl.RED, gl.RED, gl.RED, gl.ONE}      // Single-channel data as opaque grayscale
+	SwizzleGrayAlpha = Swizzle{gl.RED, gl.RED, gl.RED, gl.GREEN}    // Two-channel data as grayscale with alpha
+	SwizzleAlpha     = Swizzle{gl.ONE, gl.ONE, gl.ONE, gl.RED}      // Single-channel data as white with alpha
+)
+
+var (
+	anisotropyChecked bool
+	anisotropyMax     float32 // 0 if not supported
+)
+
+// hasExtension returns if the current context supports the extension.
+func hasExtension(name string) bool {
+	var count int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+	for i := int32(0); i < count; i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i))) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxAnisotropy returns the maximum level of anisotropic filtering supported,
+// or 0 if it is not supported at all.
+func MaxAnisotropy() float32 {
+	if !anisotropyChecked {
+		if hasExtension("GL_ARB_texture_filter_anisotropic") || hasExtension("GL_EXT_texture_filter_anisotropic") {
+			gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &anisotropyMax)
+		}
+		anisotropyChecked = true
+	}
+	return anisotropyMax
+}
+
+// clampAnisotropy clamps level into [1, MaxAnisotropy()], returning false if unsupported.
+func clampAnisotropy(level float32) (float32, bool) {
+	max := MaxAnisotropy()
+	if max == 0 {
+		return 0, false
+	}
+	if level < 1 {
+		level = 1
+	}
+	if level > max {
+		level = max
+	}
+	return level, true
+}
+
+// SetWrap sets the wrap mode of the S (horizontal) and T (vertical) coordinates.
+func (t *Texture) SetWrap(wrapS, wrapT WrapMode) {
+	defer t.bind()()
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, int32(wrapS))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, int32(wrapT))
+}
+
+// SetBorderColor sets the color used with WrapClampToBorder.
+func (t *Texture) SetBorderColor(color itype.Vec4f) {
+	defer t.bind()()
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, &color[0])
+}
+
+// SetAnisotropy sets the level of anisotropic filtering, clamped to what is supported.
+// 1 disables it. It does nothing if anisotropic filtering is not supported.
+func (t *Texture) SetAnisotropy(level float32) {
+	level, ok := clampAnisotropy(level)
+	if !ok {
+		return
+	}
+	defer t.bind()()
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MAX_ANISOTROPY, level)
+}
+
+// SetLOD sets the bias added to the computed mipmap level of detail,
+// and the range [min, max] it is clamped to.
+func (t *Texture) SetLOD(bias, min, max float32) {
+	defer t.bind()()
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_LOD_BIAS, bias)
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MIN_LOD, min)
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MAX_LOD, max)
+}
+
+// SetLevelRange sets the base and max mipmap levels that are sampled.
+func (t *Texture) SetLevelRange(base, max int32) {
+	defer t.bind()()
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_BASE_LEVEL, base)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, max)
+}
+
+// SetSwizzle sets the swizzle mask of the texture.
+func (t *Texture) SetSwizzle(swizzle Swizzle) {
+	defer t.bind()()
+	gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+}
+
+// SetWrap sets the wrap mode on all three coordinates.
+func (s *Sampler) SetWrap(mode WrapMode) {
+	gl.SamplerParameteri(s.sampler, gl.TEXTURE_WRAP_S, int32(mode))
+	gl.SamplerParameteri(s.sampler, gl.TEXTURE_WRAP_T, int32(mode))
+	gl.SamplerParameteri(s.sampler, gl.TEXTURE_WRAP_R, int32(mode))
+}
+
+// SetBorderColor sets the color used with WrapClampToBorder.
+func (s *Sampler) SetBorderColor(color itype.Vec4f) {
+	gl.SamplerParameterfv(s.sampler, gl.TEXTURE_BORDER_COLOR, &color[0])
+}
+
+// SetAnisotropy sets the level of anisotropic filtering, like Texture.SetAnisotropy.
+func (s *Sampler) SetAnisotropy(level float32) {
+	level, ok := clampAnisotropy(level)
+	if !ok {
+		return
+	}
+	gl.SamplerParameterf(s.sampler, gl.TEXTURE_MAX_ANISOTROPY, level)
+}
+
+// SetLOD sets the level of detail bias and range, like Texture.SetLOD.
+func (s *Sampler) SetLOD(bias, min, max float32) {
+	gl.SamplerParameterf(s.sampler, gl.TEXTURE_LOD_BIAS, bias)
+	gl.SamplerParameterf(s.sampler, gl.TEXTURE_MIN_LOD, min)
+	gl.SamplerParameterf(s.sampler, gl.TEXTURE_MAX_LOD, max)
+}
@@ -0,0 +1,149 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+// ResourceKind is the kind of a GPU resource created by this package.
+type ResourceKind int
+
+const (
+	ResourceShader ResourceKind = iota
+	ResourceTexture
+	ResourceTextureArray
+	ResourceTexture3D
+	ResourceSampler
+	ResourceBuffer
+	ResourceVertexArray
+	ResourceFramebuffer
+	resourceKindCount
+)
+
+var resourceKindNames = [resourceKindCount]string{
+	ResourceShader:       "Shader",
+	ResourceTexture:      "Texture",
+	ResourceTextureArray: "TextureArray",
+	ResourceTexture3D:    "Texture3D",
+	ResourceSampler:      "Sampler",
+	ResourceBuffer:       "Buffer",
+	ResourceVertexArray:  "VertexArray",
+	ResourceFramebuffer:  "Framebuffer",
+}
+
+func (k ResourceKind) String() string {
+	if k < 0 || k >= resourceKindCount {
+		return fmt.Sprintf("ResourceKind(%d)", int(k))
+	}
+	return resourceKindNames[k]
+}
+
+// Resource describes a live GPU resource.
+type Resource struct {
+	Kind   ResourceKind
+	Handle uint32
+	Memory int    // Estimated GPU memory in bytes, 0 if unknown or negligible
+	Stack  string // Stack trace of the creation, only in builds with the debug tag
+}
+
+// memoryUser is implemented by resources that can estimate their GPU memory.
+type memoryUser interface {
+	memory() int
+}
+
+type trackKey struct {
+	kind   ResourceKind
+	handle uint32
+}
+
+type trackedResource struct {
+	obj   memoryUser // nil if memory is not estimated
+	stack string
+}
+
+var (
+	trackLock sync.Mutex
+	tracked   = make(map[trackKey]trackedResource)
+)
+
+// track records a newly created resource. obj can be nil.
+func track(kind ResourceKind, handle uint32, obj memoryUser) {
+	if handle == 0 {
+		return
+	}
+	var stack string
+	if trackStacks {
+		stack = string(debug.Stack())
+	}
+
+	trackLock.Lock()
+	tracked[trackKey{kind, handle}] = trackedResource{obj: obj, stack: stack}
+	trackLock.Unlock()
+}
+
+// untrack forgets a deleted resource.
+func untrack(kind ResourceKind, handle uint32) {
+	trackLock.Lock()
+	delete(tracked, trackKey{kind, handle})
+	trackLock.Unlock()
+}
+
+// LiveResources returns all the GPU resources created and not yet freed,
+// sorted by kind and handle.
+func LiveResources() []Resource {
+	trackLock.Lock()
+	res := make([]Resource, 0, len(tracked))
+	for key, tr := range tracked {
+		r := Resource{Kind: key.kind, Handle: key.handle, Stack: tr.stack}
+		if tr.obj != nil {
+			r.Memory = tr.obj.memory()
+		}
+		res = append(res, r)
+	}
+	trackLock.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Kind != res[j].Kind {
+			return res[i].Kind < res[j].Kind
+		}
+		return res[i].Handle < res[j].Handle
+	})
+	return res
+}
+
+// LiveCount returns the number of live resources of a kind.
+func LiveCount(kind ResourceKind) int {
+	trackLock.Lock()
+	defer trackLock.Unlock()
+
+	var n int
+	for key := range tracked {
+		if key.kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// ReportLeaks writes all the live resources to w, and returns how many there are.
+// Call it at shutdown after everything is supposed to be freed.
+//
+// Creation stack traces are included in builds with the debug tag.
+func ReportLeaks(w io.Writer) int {
+	res := LiveResources()
+	if len(res) == 0 {
+		return 0
+	}
+
+	fmt.Fprintf(w, "render: %d GPU resources leaked:\n", len(res))
+	for _, r := range res {
+		fmt.Fprintf(w, "  %s %d (%d bytes)\n", r.Kind, r.Handle, r.Memory)
+		if r.Stack != "" {
+			fmt.Fprintf(w, "    created at:\n%s\n", r.Stack)
+		}
+	}
+	return len(res)
+}
@@ -0,0 +1,7 @@
+//go:build debug
+// +build debug
+
+package render
+
+// trackStacks records creation stack traces of resources in debug builds.
+const trackStacks = true
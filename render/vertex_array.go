@@ -0,0 +1,136 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// Attrib describes one vertex attribute of a VertexArray, sourced from a Buffer.
+type Attrib struct {
+	Name   string  // Name of the attribute in the shader
+	Buffer *Buffer // Vertex buffer holding the data
+
+	Size       int32  // Number of components, 1 to 4
+	Type       uint32 // Component type, gl.FLOAT if 0
+	Normalized bool   // Map integer types into [0, 1] or [-1, 1]
+	Integer    bool   // Keep integer types as integers (ivec/uvec in the shader)
+
+	Stride int32 // Bytes between two vertices, 0 if tightly packed
+	Offset int   // Offset of the first component in the buffer, in bytes
+
+	// Divisor makes the attribute instanced, advancing once per Divisor instances
+	// instead of once per vertex. 0 is per vertex.
+	Divisor uint32
+}
+
+// VertexArray holds handle to an OpenGL Vertex Array object, with the
+// attribute layout resolved against a Shader.
+type VertexArray struct {
+	vao   uint32
+	index *Buffer
+}
+
+// NewVertexArray creates a new VertexArray, resolving the attribute locations
+// with shader.GetAttribLocation. index can be nil if no indexed drawing is used.
+//
+// An attribute not found in the shader (e.g., optimized out) is an error.
+func NewVertexArray(shader *Shader, attribs []Attrib, index *Buffer) (*VertexArray, error) {
+	var lastVao, lastArrayBuffer int32
+	gl.GetIntegerv(gl.VERTEX_ARRAY_BINDING, &lastVao)
+	gl.GetIntegerv(gl.ARRAY_BUFFER_BINDING, &lastArrayBuffer)
+	defer gl.BindVertexArray(uint32(lastVao))
+	defer gl.BindBuffer(gl.ARRAY_BUFFER, uint32(lastArrayBuffer))
+
+	v := &VertexArray{index: index}
+	gl.GenVertexArrays(1, &v.vao)
+	gl.BindVertexArray(v.vao)
+	track(ResourceVertexArray, v.vao, nil)
+
+	for _, a := range attribs {
+		loc := int32(shader.GetAttribLocation(a.Name))
+		if loc == -1 {
+			v.Free()
+			return nil, fmt.Errorf("render: vertex attribute \"%s\" not found in shader", a.Name)
+		}
+		if a.Buffer == nil {
+			v.Free()
+			return nil, fmt.Errorf("render: vertex attribute \"%s\" has no buffer", a.Name)
+		}
+
+		xtype := a.Type
+		if xtype == 0 {
+			xtype = gl.FLOAT
+		}
+
+		gl.BindBuffer(gl.ARRAY_BUFFER, a.Buffer.buf)
+		gl.EnableVertexAttribArray(uint32(loc))
+		if a.Integer {
+			gl.VertexAttribIPointerWithOffset(uint32(loc), a.Size, xtype, a.Stride, uintptr(a.Offset))
+		} else {
+			gl.VertexAttribPointerWithOffset(uint32(loc), a.Size, xtype, a.Normalized, a.Stride, uintptr(a.Offset))
+		}
+		gl.VertexAttribDivisor(uint32(loc), a.Divisor)
+	}
+
+	if index != nil {
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, index.buf)
+	}
+
+	return v, nil
+}
+
+// bind binds the vertex array, returning a function that restores the previous binding.
+func (v *VertexArray) bind() (restore func()) {
+	var last int32
+	gl.GetIntegerv(gl.VERTEX_ARRAY_BINDING, &last)
+	gl.BindVertexArray(v.vao)
+	return func() { gl.BindVertexArray(uint32(last)) }
+}
+
+// Draw draws count vertices starting from first with glDrawArrays.
+// mode is the primitive type, e.g., gl.TRIANGLES.
+//
+// The shader program and textures must be set up by the caller.
+func (v *VertexArray) Draw(mode uint32, first, count int32) {
+	defer v.bind()()
+	gl.DrawArrays(mode, first, count)
+}
+
+// DrawInstanced draws instances copies of count vertices with glDrawArraysInstanced.
+func (v *VertexArray) DrawInstanced(mode uint32, first, count, instances int32) {
+	defer v.bind()()
+	gl.DrawArraysInstanced(mode, first, count, instances)
+}
+
+// DrawIndexed draws count indices starting from index first, like glDrawElements.
+// If count is negative, all the indices from first are drawn.
+func (v *VertexArray) DrawIndexed(mode uint32, first, count int32) {
+	v.DrawIndexedInstanced(mode, first, count, 1)
+}
+
+// DrawIndexedInstanced draws instances copies of count indices with glDrawElementsInstanced.
+func (v *VertexArray) DrawIndexedInstanced(mode uint32, first, count, instances int32) {
+	if v.index == nil {
+		return
+	}
+	if count < 0 {
+		count = int32(v.index.IndexCount()) - first
+	}
+	defer v.bind()()
+	gl.DrawElementsInstanced(mode, count, v.index.indexType, gl.PtrOffset(int(first)*v.index.indexSize), instances)
+}
+
+// Handle returns the OpenGL handle of the vertex array.
+func (v *VertexArray) Handle() uint32 {
+	return v.vao
+}
+
+// Free deletes the vertex array. The buffers are not deleted.
+func (v *VertexArray) Free() {
+	if v.vao != 0 {
+		untrack(ResourceVertexArray, v.vao)
+		gl.DeleteVertexArrays(1, &v.vao)
+		v.vao = 0
+	}
+}